@@ -0,0 +1,56 @@
+package kakasi
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/sarumaj/go-kakasi/internal/kanji"
+)
+
+const (
+	SourceKanwa = kanji.SourceKanwa
+	SourceUser  = kanji.SourceUser
+)
+
+// Source is the dictionary a candidate reading is looked up in, e.g. SourceKanwa.
+type Source = kanji.Source
+
+// Candidate is a reading of the kanji phrase at the beginning of a segment.
+// The phrase of a candidate may be shorter than the segment, e.g. "今" for the segment "今日".
+type Candidate = kanji.Candidate
+
+// CandidateSegment is a converted segment and the candidate readings of its kanji phrase.
+type CandidateSegment struct {
+	IConverted
+	Candidates []Candidate `json:"candidates"` // empty for segments without a kanji phrase
+}
+
+// ConvertCandidates converts the input text like Convert and returns each segment with its candidate readings.
+// The candidates are the readings of all phrases at the beginning of the segment which apply to the preceding text,
+// ranked like by Convert: the readings of longer phrases first, the user dictionary before the kanwa dictionary,
+// and the readings of a phrase in the order of the dictionary.
+// The candidate of rank 0 is the reading of the segment, in the lattice segmentation mode it precedes the others.
+// The segments with other readings of the same phrase are flagged as Ambiguous.
+func (k Kakasi) ConvertCandidates(text string) ([]CandidateSegment, error) {
+	if err := k.checkInputSize(utf8.RuneCountInString(text)); err != nil {
+		return nil, err
+	}
+
+	runes, spans := decodeSpans([]byte(text), 0, 0)
+	if k.opts.normalization {
+		runes, spans, _ = normalizeSpans(runes, spans, true)
+	}
+
+	var results []CandidateSegment
+	s := k.newScanner(runes)
+	s.candidates = true
+	_, err := k.convertSegments(context.Background(), s, spans, true, func(result IConverted, seg segment) error {
+		results = append(results, CandidateSegment{IConverted: result, Candidates: seg.candidates})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
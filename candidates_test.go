@@ -0,0 +1,93 @@
+package kakasi
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConvertCandidates(t *testing.T) {
+	type want struct {
+		Orig       string
+		Hira       string
+		Ambiguous  bool
+		Candidates []string
+	}
+
+	for _, tt := range []struct {
+		name  string
+		opts  []Option
+		words [][2]string
+		args  string
+		want  []want
+	}{
+		{"ambiguous", nil, nil, "生物", []want{
+			{"生物", "せいぶつ", true, []string{"生物:せいぶつ:kanwa", "生物:なまもの:kanwa", "生:なま:kanwa"}},
+		}},
+		{"unambiguous", nil, nil, "東京へ", []want{
+			{"東京", "とうきょう", false, []string{"東京:とうきょう:kanwa", "東:ひがし:kanwa", "東:あずま:kanwa"}},
+			{"へ", "へ", false, nil},
+		}},
+		{"user", nil, [][2]string{{"生物", "なまもの"}}, "生物", []want{
+			{"生物", "なまもの", false, []string{"生物:なまもの:user", "生物:せいぶつ:kanwa", "生:なま:kanwa"}},
+		}},
+		{"lattice", []Option{WithSegmentation(SegmentationLattice)}, nil, "日本語学校", []want{
+			{"日本語", "にほんご", true, []string{"日本語:にほんご:kanwa", "日本語学:にほんごがく:kanwa", "日本語:にっぽんご:kanwa"}},
+			{"学校", "がっこう", false, []string{"学校:がっこう:kanwa", "学:がく:kanwa", "学:まなぶ:kanwa"}},
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := NewKakasi(tt.opts...)
+			if err != nil {
+				t.Errorf("NewKakasi() error: %v", err)
+				return
+			}
+
+			for _, w := range tt.words {
+				if err := k.AddWord(w[0], w[1]); err != nil {
+					t.Errorf("(*Kakasi).AddWord(%q, %q) error: %v", w[0], w[1], err)
+					return
+				}
+			}
+
+			converted, err := k.ConvertCandidates(tt.args)
+			if err != nil {
+				t.Errorf("(*Kakasi).ConvertCandidates(%q) error: %v", tt.args, err)
+				return
+			}
+
+			var got []want
+			for _, v := range converted {
+				w := want{v.Orig, v.Hira, v.Ambiguous, nil}
+				for i, c := range v.Candidates {
+					if c.Rank != i {
+						t.Errorf("(*Kakasi).ConvertCandidates(%q) ranked %q %d, want %d", tt.args, c.Yomi, c.Rank, i)
+					}
+
+					// the first candidates of each segment suffice
+					if i < 3 {
+						w.Candidates = append(w.Candidates, c.Orig+":"+c.Yomi+":"+string(c.Source))
+					}
+				}
+
+				got = append(got, w)
+			}
+
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("(*Kakasi).ConvertCandidates(%q) {\"-\": got, \"+\": want}: %s", tt.args, diff)
+			}
+
+			plain, err := k.Convert(tt.args)
+			if err != nil {
+				t.Errorf("(*Kakasi).Convert(%q) error: %v", tt.args, err)
+				return
+			}
+
+			for i, v := range plain {
+				if i < len(converted) && (v != converted[i].IConverted || (len(converted[i].Candidates) > 0 && converted[i].Candidates[0].Yomi != v.Hira)) {
+					t.Errorf("(*Kakasi).Convert(%q)[%d] = %v, want %v", tt.args, i, v, converted[i].IConverted)
+				}
+			}
+		})
+	}
+}
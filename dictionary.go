@@ -0,0 +1,69 @@
+package kakasi
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sarumaj/go-kakasi/internal/codegen"
+)
+
+// AddWord adds a word to the user dictionary of the instance, e.g. a company name or a corrected reading.
+// The kanji must begin with a kanji character, the yomi is given in hiragana.
+// Like in the kanwa dictionary, the yomi may end with an okurigana tail letter,
+// e.g. ("書", "かk") adds "書か", "書き", "書く", "書け" and "書こ".
+// The optional ctx restricts the reading to phrases in one of the contexts.
+// A plain context matches if the text between the beginning of the segment and the phrase equals it,
+// further conditions match the preceding or the following characters, e.g. "<月" (preceded by 月),
+// ">を" (followed by を), "<~[0-9]" and ">~[なに]" (regular expressions), "pos:na" (adjectival noun)
+// and "!>中" (not followed by 中), which are joined by "&" to match all of them, e.g. "<枚&>だ".
+// See the README for the full syntax.
+// The words of the user dictionary take priority over the embedded dictionary for matches of the same length,
+// while longer matches of the embedded dictionary still win.
+// The user dictionary may be updated while other goroutines are converting.
+// It is built on the first conversion after an update, thus adding many words in a row builds it once,
+// while alternating additions and conversions rebuild it each time, in which case LoadUserDictionary is preferable.
+func (k Kakasi) AddWord(kanji, yomi string, ctx ...string) error {
+	return k.jConv.AddWord(kanji, yomi, ctx...)
+}
+
+// RemoveWord removes all words with the given kanji from the user dictionary of the instance.
+// It returns true if any word was removed. Like AddWord, the user dictionary is built on the next conversion.
+func (k Kakasi) RemoveWord(kanji string) bool {
+	return k.jConv.RemoveWord(kanji)
+}
+
+// LoadUserDictionary adds the words of a dictionary in the format of the kakasidict to the user dictionary,
+// i.e. lines of "yomi kanji [ctx ...]" with the same okurigana convention as AddWord.
+// Empty lines and lines starting with ";;" are skipped.
+// Either all or none of the words are added, malformed lines are reported with their line numbers.
+func (k Kakasi) LoadUserDictionary(r io.Reader) error {
+	return k.jConv.LoadWords(r, codegen.ParseKanwa)
+}
+
+// LoadSKKDictionary adds the words of a dictionary in the format of the SKK-JISYO to the user dictionary,
+// i.e. lines of "yomi /candidate1/candidate2;annotation/" encoded in UTF-8.
+// The SKK-JISYO files distributed in EUC-JP must be converted to UTF-8 first, e.g. with iconv, otherwise an error is returned.
+// The annotations are stripped, okuri-ari entries (e.g. "おくr /送/") are expanded like the tail letters of AddWord.
+// Entries which are no readings of kanji, e.g. abbreviations or Lisp expressions, are skipped.
+// Either all or none of the words are added, malformed lines are reported with their line numbers.
+func (k Kakasi) LoadSKKDictionary(r io.Reader) error {
+	return k.jConv.LoadWords(r, codegen.ParseSKK)
+}
+
+// loadDictionaryFile loads a user dictionary from a file with the given load method,
+// e.g. (Kakasi).LoadUserDictionary.
+func (k Kakasi) loadDictionaryFile(path string, load func(Kakasi, io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if err := load(k, f); err != nil {
+		return fmt.Errorf("user dictionary %s: %w", path, err)
+	}
+
+	return nil
+}
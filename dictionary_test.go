@@ -0,0 +1,274 @@
+package kakasi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUserDictionary(t *testing.T) {
+	type word struct {
+		kanji, yomi string
+		ctx         []string
+	}
+
+	for _, tt := range []struct {
+		name  string
+		words []word
+		args  string
+		want  []string
+	}{
+		{"none", nil, "今日", []string{"きょう"}},
+		{"same length", []word{{"今日", "こんにち", nil}}, "今日", []string{"こんにち"}},
+		{"longer", []word{{"日本電気", "にっぽんでんき", nil}}, "日本電気の", []string{"にっぽんでんき", "の"}},
+		{"shorter", []word{{"日", "ひ", nil}}, "日本", []string{"にっぽん"}},
+		{"okurigana", []word{{"翔", "とk", nil}}, "翔ぶ翔べ", []string{"とぶ", "とべ"}},
+		{"replaced", []word{{"今日", "こんにち", nil}, {"今日", "きょう", nil}}, "今日", []string{"きょう"}},
+		{"context", []word{{"日", "にち", []string{"の"}}, {"日", "び", nil}}, "日の日", []string{"び", "の", "にち"}},
+		{"itaiji", []word{{"髙田", "たかだ", nil}}, "高田", []string{"たかだ"}},
+		{"not preceded", []word{{"一日", "いちにち", []string{"!<月"}}}, "一日", []string{"いちにち"}},
+		{"preceded", []word{{"一日", "いちにち", []string{"!<月"}}}, "五月一日", []string{"ごがつ", "ついたち"}},
+		{"followed", []word{{"上手", "うわて", []string{">を"}}}, "上手を上手な", []string{"うわて", "を", "じょうず", "な"}},
+		{"regexp", []word{{"上手", "うわて", []string{">~[をが]"}}}, "上手が上手に", []string{"うわて", "が", "じょうず", "に"}},
+		{"pos", []word{{"上手", "うわて", []string{"pos:noun"}}}, "上手。上手だ", []string{"うわて。", "じょうず", "だ"}},
+		{"conjunction", []word{{"上手", "うわて", []string{"<枚&pos:noun"}}}, "三枚上手が上手が", []string{"さんまい", "うわて", "が", "じょうず", "が"}},
+		{"dictionary date", nil, "五月一日に", []string{"ごがつ", "ついたち", "に"}},
+		{"dictionary duration", nil, "一日に三回", []string{"いちにち", "に", "さんかい"}},
+		{"dictionary preceded and followed", nil, "二枚上手だ", []string{"にまい", "うわて", "だ"}},
+		{"dictionary preceded only", nil, "二枚上手に", []string{"にまい", "じょうず", "に"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := NewKakasi()
+			if err != nil {
+				t.Errorf("NewKakasi() error: %v", err)
+				return
+			}
+
+			for _, w := range tt.words {
+				if err := k.AddWord(w.kanji, w.yomi, w.ctx...); err != nil {
+					t.Errorf("(*Kakasi).AddWord(%q, %q, %q) error: %v", w.kanji, w.yomi, w.ctx, err)
+					return
+				}
+			}
+
+			converted, err := k.Convert(tt.args)
+			if err != nil {
+				t.Errorf("(*Kakasi).Convert(%q) error: %v", tt.args, err)
+				return
+			}
+
+			var got []string
+			for _, v := range converted {
+				got = append(got, v.Hira)
+			}
+
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("(*Kakasi).Convert(%q) {\"-\": got, \"+\": want}: %s", tt.args, diff)
+			}
+		})
+	}
+}
+
+func TestAddWordContext(t *testing.T) {
+	k, err := NewKakasi()
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	for _, ctx := range []string{"pos:verb", "<~[", ">", "!"} {
+		if err := k.AddWord("上手", "うわて", ctx); err == nil || !strings.Contains(err.Error(), "invalid context") {
+			t.Errorf("(*Kakasi).AddWord(%q, %q, %q) error: %v, want invalid context", "上手", "うわて", ctx, err)
+		}
+	}
+}
+
+func TestRemoveWord(t *testing.T) {
+	k, err := NewKakasi()
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	for _, args := range [][2]string{{"今日", "こんにち"}, {"今日", "こんにちk"}} {
+		if err := k.AddWord(args[0], args[1]); err != nil {
+			t.Errorf("(*Kakasi).AddWord(%q, %q) error: %v", args[0], args[1], err)
+			return
+		}
+	}
+
+	if converted, _ := k.Convert("今日"); converted[0].Hira != "こんにち" {
+		t.Errorf("(*Kakasi).Convert(%q) = %q, want %q", "今日", converted[0].Hira, "こんにち")
+	}
+
+	if !k.RemoveWord("今日") {
+		t.Errorf("(*Kakasi).RemoveWord(%q) = false, want true", "今日")
+	}
+
+	if k.RemoveWord("今日") {
+		t.Errorf("(*Kakasi).RemoveWord(%q) = true, want false", "今日")
+	}
+
+	if converted, _ := k.Convert("今日"); converted[0].Hira != "きょう" {
+		t.Errorf("(*Kakasi).Convert(%q) = %q, want %q", "今日", converted[0].Hira, "きょう")
+	}
+}
+
+func TestAddWordInvalid(t *testing.T) {
+	k, err := NewKakasi()
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	for _, args := range [][2]string{{"", "よみ"}, {"かな", "かな"}, {"漢字", ""}, {"書", "k"}, {"書", "かq"}} {
+		if err := k.AddWord(args[0], args[1]); err == nil {
+			t.Errorf("(*Kakasi).AddWord(%q, %q) error: nil, want error", args[0], args[1])
+		}
+	}
+}
+
+func TestAddWordConcurrent(t *testing.T) {
+	k, err := NewKakasi()
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				converted, err := k.Convert("今日")
+				if err != nil {
+					t.Errorf("(*Kakasi).Convert(%q) error: %v", "今日", err)
+					return
+				}
+
+				if hira := converted[0].Hira; hira != "きょう" && hira != "こんにち" {
+					t.Errorf("(*Kakasi).Convert(%q) = %q", "今日", hira)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := range 50 {
+		if i%2 == 0 {
+			_ = k.AddWord("今日", "こんにち")
+		} else {
+			_ = k.RemoveWord("今日")
+		}
+	}
+
+	wg.Wait()
+}
+
+func TestLoadUserDictionary(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		dict    string
+		args    string
+		want    []string
+		wantErr string
+	}{
+		{"words", ";; comment\n\nにっぽんでんき 日本電気\nとk 翔\n", "日本電気の翔ぶ", []string{"にっぽんでんき", "の", "とぶ"}, ""},
+		{"context", "にち 日 の\nび 日\n", "日の日", []string{"び", "の", "にち"}, ""},
+		{"malformed", "こんにち 今日\nにっぽん\n", "今日", []string{"きょう"}, "line 2"},
+		{"invalid", "こんにち 今日\n\nにっぽん nippon\n", "今日", []string{"きょう"}, "line 3: invalid kanji"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := NewKakasi()
+			if err != nil {
+				t.Errorf("NewKakasi() error: %v", err)
+				return
+			}
+
+			err = k.LoadUserDictionary(strings.NewReader(tt.dict))
+			if (err == nil) != (tt.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("(*Kakasi).LoadUserDictionary(%q) error: %v, want %q", tt.dict, err, tt.wantErr)
+				return
+			}
+
+			converted, err := k.Convert(tt.args)
+			if err != nil {
+				t.Errorf("(*Kakasi).Convert(%q) error: %v", tt.args, err)
+				return
+			}
+
+			var got []string
+			for _, v := range converted {
+				got = append(got, v.Hira)
+			}
+
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("(*Kakasi).Convert(%q) {\"-\": got, \"+\": want}: %s", tt.args, diff)
+			}
+		})
+	}
+}
+
+func TestWithUserDictionaryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user.utf8")
+	if err := os.WriteFile(path, []byte("こんにち 今日\n"), 0o644); err != nil {
+		t.Errorf("os.WriteFile(%q) error: %v", path, err)
+		return
+	}
+
+	k, err := NewKakasi(WithUserDictionaryFile(path))
+	if err != nil {
+		t.Errorf("NewKakasi(WithUserDictionaryFile(%q)) error: %v", path, err)
+		return
+	}
+
+	if converted, _ := k.Convert("今日"); converted[0].Hira != "こんにち" {
+		t.Errorf("(*Kakasi).Convert(%q) = %q, want %q", "今日", converted[0].Hira, "こんにち")
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.utf8")
+	if _, err := NewKakasi(WithUserDictionaryFile(missing)); err == nil {
+		t.Errorf("NewKakasi(WithUserDictionaryFile(%q)) error: nil, want error", missing)
+	}
+}
+
+func TestLoadSKKDictionary(t *testing.T) {
+	dict := ";; okuri-ari entries.\nとr /翔;fly/[ぶ/翔/]/\n;; okuri-nasi entries.\nこんにち /今日/\n"
+	args, want := "今日も翔ぶ", []string{"こんにち", "も", "とぶ"}
+
+	path := filepath.Join(t.TempDir(), "SKK-JISYO.user")
+	if err := os.WriteFile(path, []byte(dict), 0o644); err != nil {
+		t.Errorf("os.WriteFile(%q) error: %v", path, err)
+		return
+	}
+
+	k, err := NewKakasi(WithSKKDictionaryFile(path))
+	if err != nil {
+		t.Errorf("NewKakasi(WithSKKDictionaryFile(%q)) error: %v", path, err)
+		return
+	}
+
+	converted, err := k.Convert(args)
+	if err != nil {
+		t.Errorf("(*Kakasi).Convert(%q) error: %v", args, err)
+		return
+	}
+
+	var got []string
+	for _, v := range converted {
+		got = append(got, v.Hira)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("(*Kakasi).Convert(%q) {\"-\": got, \"+\": want}: %s", args, diff)
+	}
+
+	if err := k.LoadSKKDictionary(strings.NewReader("こんにち 今日\n")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("(*Kakasi).LoadSKKDictionary() error: %v, want %q", err, "line 1")
+	}
+}
@@ -0,0 +1,26 @@
+package kakasi
+
+import "fmt"
+
+// ErrInputTooLarge is returned when the input text exceeds the limit set by WithMaxInputRunes.
+type ErrInputTooLarge struct {
+	Limit int // maximum number of characters
+	Size  int // number of characters of the input text, for streams the number of characters read so far
+}
+
+// Error implements the error interface.
+func (e *ErrInputTooLarge) Error() string {
+	return fmt.Sprintf("input text too large: %d characters exceed the limit of %d characters", e.Size, e.Limit)
+}
+
+// ErrPassportName is returned when a romanized name does not comply with the rules of the Japanese passport for its reading.
+type ErrPassportName struct {
+	Name    string // romanized name
+	Reading string // reading of the name
+	Want    string // romanized name in the Hepburn notation
+}
+
+// Error implements the error interface.
+func (e *ErrPassportName) Error() string {
+	return fmt.Sprintf("passport name %q does not comply with the reading %q, want %q", e.Name, e.Reading, e.Want)
+}
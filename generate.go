@@ -13,9 +13,27 @@ import (
 var logger = log.New(os.Stderr, "codegen: ", 0)
 var buildDir = flag.String("buildDir", "build", "build directory")
 var indent = flag.String("indent", "", "indentation string")
+var lexicon = flag.String("lexicon", "", "lexicon CSV (e.g. lex.csv of UniDic) to regenerate the unidict sources from")
+var lexiconFormat = flag.String("lexiconFormat", "unidic", "format of the lexicon CSV: unidic or ipadic")
+var lexiconName = flag.String("lexiconName", "", "name of the lexicon in the headers of the unidict sources (e.g. \"unidic v3.1.0\"), defaults to the file name")
 
 func main() {
 	flag.Parse()
+	if *lexicon != "" {
+		format, ok := map[string]codegen.LexiconFormat{
+			"unidic": codegen.UniDicFormat,
+			"ipadic": codegen.IPADicFormat,
+		}[*lexiconFormat]
+		if !ok {
+			logger.Fatalf("unknown lexicon format: %s\n", *lexiconFormat)
+		}
+
+		logger.Printf("Generating kanwa sources from %s\n", *lexicon)
+		if err := codegen.GenerateKanwaSources("data", *lexicon, *lexiconName, format); err != nil {
+			logger.Fatalln(err)
+		}
+	}
+
 	logger.Printf("Generating code in %s\n", *buildDir)
 
 	if err := codegen.Generate(*buildDir, *indent); err != nil {
@@ -0,0 +1,144 @@
+package codegen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// BinaryVersion is the version of the binary dictionary format.
+// It is increased on every incompatible change of the format.
+const BinaryVersion = 2
+
+// binaryMagic is the signature at the beginning of a binary dictionary.
+const binaryMagic = "KKSD"
+
+// binaryHeaderLen is the length of the header of a binary dictionary in bytes.
+const binaryHeaderLen = 16
+
+const (
+	binaryLookupMap binaryKind = iota + 1
+	binaryTransTable
+	binaryKanwaMap
+)
+
+// binaryKind is the kind of the dictionary stored in the binary format.
+type binaryKind uint16
+
+// table is a sorted string table.
+// It is a list of records sorted by their first field, each record being a list of string fields.
+//
+// The binary format of a table is (all integers are little-endian):
+//
+//	magic    [4]byte      "KKSD"
+//	version  uint16       BinaryVersion
+//	kind     uint16       binaryKind
+//	records  uint32       number of records n
+//	size     uint32       length of data
+//	offsets  [n+1]uint32  offset of each record in data, followed by the length of data
+//	data     []byte       concatenated records
+//
+// Each record is a sequence of fields, each field is its length encoded as uvarint followed by its bytes.
+// The fields are substrings of data, thus the table is loaded with a single copy of the data.
+type table struct {
+	offsets []uint32
+	data    string
+}
+
+// len returns the number of records.
+func (t table) len() int { return len(t.offsets) - 1 }
+
+// record appends the fields of the i-th record to fields and returns the extended slice.
+func (t table) record(i int, fields []string) ([]string, error) {
+	r := t.data[t.offsets[i]:t.offsets[i+1]]
+	for len(r) > 0 {
+		l, n := uvarint(r)
+		if n == 0 || l > uint64(len(r)-n) {
+			return nil, fmt.Errorf("invalid binary dictionary: corrupted record %d", i)
+		}
+
+		fields, r = append(fields, r[n:n+int(l)]), r[n+int(l):]
+	}
+
+	return fields, nil
+}
+
+// uvarint decodes an unsigned integer encoded as uvarint at the beginning of s like binary.Uvarint.
+// It returns the number of bytes read, 0 if s does not begin with a valid uvarint.
+func uvarint(s string) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(s) && i < binary.MaxVarintLen64; i++ {
+		v |= uint64(s[i]&0x7f) << (7 * i)
+		if s[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+
+	return 0, 0
+}
+
+// decodeTable decodes a table of the given kind.
+// It returns an error if the data is not a valid table of that kind.
+func decodeTable(kind binaryKind, data []byte) (table, error) {
+	if len(data) < binaryHeaderLen || string(data[:4]) != binaryMagic {
+		return table{}, fmt.Errorf("invalid binary dictionary: missing signature")
+	}
+
+	if v := binary.LittleEndian.Uint16(data[4:]); v != BinaryVersion {
+		return table{}, fmt.Errorf("unsupported binary dictionary version: %d", v)
+	}
+
+	if k := binaryKind(binary.LittleEndian.Uint16(data[6:])); k != kind {
+		return table{}, fmt.Errorf("invalid binary dictionary: unexpected kind %d, want %d", k, kind)
+	}
+
+	n, size := uint64(binary.LittleEndian.Uint32(data[8:])), uint64(binary.LittleEndian.Uint32(data[12:]))
+	if uint64(len(data)) != binaryHeaderLen+4*(n+1)+size {
+		return table{}, fmt.Errorf("invalid binary dictionary: unexpected length %d", len(data))
+	}
+
+	offsets := make([]uint32, n+1)
+	for i := range offsets {
+		offsets[i] = binary.LittleEndian.Uint32(data[binaryHeaderLen+4*i:])
+		if (i == 0 && offsets[i] != 0) || (i > 0 && offsets[i] < offsets[i-1]) {
+			return table{}, fmt.Errorf("invalid binary dictionary: corrupted offsets")
+		}
+	}
+
+	if uint64(offsets[n]) != size {
+		return table{}, fmt.Errorf("invalid binary dictionary: corrupted offsets")
+	}
+
+	return table{offsets: offsets, data: string(data[binaryHeaderLen+4*(n+1):])}, nil
+}
+
+// encodeTable encodes the records as a table of the given kind.
+// The records are sorted by their first field, the order of records with equal first fields is preserved.
+func encodeTable(kind binaryKind, records [][]string) []byte {
+	slices.SortStableFunc(records, func(a, b []string) int { return strings.Compare(a[0], b[0]) })
+
+	var data []byte
+	offsets := make([]uint32, 0, len(records)+1)
+	for _, r := range records {
+		offsets = append(offsets, uint32(len(data)))
+		for _, f := range r {
+			data = binary.AppendUvarint(data, uint64(len(f)))
+			data = append(data, f...)
+		}
+	}
+
+	offsets = append(offsets, uint32(len(data)))
+
+	out := make([]byte, 0, binaryHeaderLen+4*len(offsets)+len(data))
+	out = append(out, binaryMagic...)
+	out = binary.LittleEndian.AppendUint16(out, BinaryVersion)
+	out = binary.LittleEndian.AppendUint16(out, uint16(kind))
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(records)))
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(data)))
+	for _, v := range offsets {
+		out = binary.LittleEndian.AppendUint32(out, v)
+	}
+
+	return append(out, data...)
+}
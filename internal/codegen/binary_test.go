@@ -0,0 +1,174 @@
+package codegen
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestLookupMap_MarshalBinary(t *testing.T) {
+	for _, src := range lookupMapResources {
+		t.Run(src, func(t *testing.T) {
+			m, err := makeLookupMap(src)
+			if err != nil {
+				t.Errorf("makeLookupMap() error = %v", err)
+				return
+			}
+
+			data, err := m.MarshalBinary()
+			if err != nil {
+				t.Errorf("LookupMap.MarshalBinary() error = %v", err)
+				return
+			}
+
+			var got LookupMap
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Errorf("(*LookupMap).UnmarshalBinary() error = %v", err)
+				return
+			}
+
+			if got.Len() != m.Len() {
+				t.Errorf("(*LookupMap).UnmarshalBinary() decoded %d entries, want %d", got.Len(), m.Len())
+			}
+
+			for _, k := range m.Keys() {
+				if !got.Has(k) || got.Get(k) != m.Get(k) {
+					t.Errorf("(*LookupMap).UnmarshalBinary() decoded %q: %q, want %q", k, got.Get(k), m.Get(k))
+				}
+			}
+		})
+	}
+}
+
+func TestTransTable_MarshalBinary(t *testing.T) {
+	for _, src := range transTableResources {
+		t.Run(src, func(t *testing.T) {
+			m, err := makeTransTable(src)
+			if err != nil {
+				t.Errorf("makeTransTable() error = %v", err)
+				return
+			}
+
+			data, err := m.MarshalBinary()
+			if err != nil {
+				t.Errorf("TransTable.MarshalBinary() error = %v", err)
+				return
+			}
+
+			var got TransTable
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Errorf("(*TransTable).UnmarshalBinary() error = %v", err)
+				return
+			}
+
+			want := make(map[rune]*string)
+			iterator := m.Iter()
+			for k, v, ok := iterator(); ok; k, v, ok = iterator() {
+				want[k] = v
+			}
+
+			decoded := make(map[rune]*string)
+			iterator = got.Iter()
+			for k, v, ok := iterator(); ok; k, v, ok = iterator() {
+				decoded[k] = v
+			}
+
+			if !reflect.DeepEqual(decoded, want) {
+				t.Errorf("(*TransTable).UnmarshalBinary() decoded %d entries, not equal to the %d entries encoded", len(decoded), len(want))
+			}
+		})
+	}
+}
+
+func TestKanwaMap_MarshalBinary(t *testing.T) {
+	for dst, src_list := range kanwaMapResources {
+		t.Run(dst, func(t *testing.T) {
+			m, err := makeKanwaMap(src_list)
+			if err != nil {
+				t.Errorf("makeKanwaMap() error = %v", err)
+				return
+			}
+
+			data, err := m.MarshalBinary()
+			if err != nil {
+				t.Errorf("KanwaMap.MarshalBinary() error = %v", err)
+				return
+			}
+
+			var got KanwaMap
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Errorf("(*KanwaMap).UnmarshalBinary() error = %v", err)
+				return
+			}
+
+			if got.Len() != m.Len() {
+				t.Errorf("(*KanwaMap).UnmarshalBinary() decoded %d tables, want %d", got.Len(), m.Len())
+			}
+
+			for _, c := range m.Keys() {
+				table, gotTable := m.Get(c), got.Get(c)
+				if gotTable.Len() != table.Len() {
+					t.Errorf("(*KanwaMap).UnmarshalBinary() decoded %d phrases of %q, want %d", gotTable.Len(), c, table.Len())
+					continue
+				}
+
+				for _, k := range table.Keys() {
+					if !reflect.DeepEqual(gotTable.Get(k), table.Get(k)) {
+						t.Errorf("(*KanwaMap).UnmarshalBinary() decoded %q: %v, want %v", k, gotTable.Get(k), table.Get(k))
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_decodeTable(t *testing.T) {
+	valid := encodeTable(binaryLookupMap, [][]string{{"b", "2"}, {"a", "1"}})
+
+	corrupt := func(f func(data []byte) []byte) []byte {
+		return f(append([]byte(nil), valid...))
+	}
+
+	for _, tt := range []struct {
+		name    string
+		kind    binaryKind
+		data    []byte
+		wantErr bool
+	}{
+		{"valid", binaryLookupMap, valid, false},
+		{"empty", binaryLookupMap, nil, true},
+		{"signature", binaryLookupMap, corrupt(func(d []byte) []byte { d[0] = 'X'; return d }), true},
+		{"version", binaryLookupMap, corrupt(func(d []byte) []byte { binary.LittleEndian.PutUint16(d[4:], BinaryVersion+1); return d }), true},
+		{"kind", binaryKanwaMap, valid, true},
+		{"truncated", binaryLookupMap, valid[:len(valid)-1], true},
+		{"offsets", binaryLookupMap, corrupt(func(d []byte) []byte { binary.LittleEndian.PutUint32(d[binaryHeaderLen+4:], 99); return d }), true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeTable(tt.kind, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeTable() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+
+	table, err := decodeTable(binaryLookupMap, valid)
+	if err != nil {
+		t.Errorf("decodeTable() error = %v", err)
+		return
+	}
+
+	var got [][]string
+	for i := range table.len() {
+		record, err := table.record(i, nil)
+		if err != nil {
+			t.Errorf("table.record(%d) error = %v", i, err)
+			return
+		}
+
+		got = append(got, record)
+	}
+
+	if want := [][]string{{"a", "1"}, {"b", "2"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeTable() decoded %q, want %q", got, want)
+	}
+}
@@ -7,7 +7,7 @@ import (
 )
 
 // Generate is a code generation function that generates lookup maps, translation tables, and kanwa maps.
-// The generated files are written to the specified directory.
+// The generated files are written to the specified directory, each in the JSON and in the binary format.
 func Generate(dst, indent string) error {
 	for tgt, src := range lookupMapResources {
 		m, err := makeLookupMap(src)
@@ -18,6 +18,10 @@ func Generate(dst, indent string) error {
 		if err := dumpJSON(filepath.Join(dst, tgt), m, indent); err != nil {
 			return err
 		}
+
+		if err := dumpBinary(filepath.Join(dst, tgt), m); err != nil {
+			return err
+		}
 	}
 
 	for tgt, src := range transTableResources {
@@ -29,6 +33,10 @@ func Generate(dst, indent string) error {
 		if err := dumpJSON(filepath.Join(dst, tgt), m, indent); err != nil {
 			return err
 		}
+
+		if err := dumpBinary(filepath.Join(dst, tgt), m); err != nil {
+			return err
+		}
 	}
 
 	for tgt, src_list := range kanwaMapResources {
@@ -40,6 +48,10 @@ func Generate(dst, indent string) error {
 		if err := dumpJSON(filepath.Join(dst, tgt), m, indent); err != nil {
 			return err
 		}
+
+		if err := dumpBinary(filepath.Join(dst, tgt), m); err != nil {
+			return err
+		}
 	}
 
 	return nil
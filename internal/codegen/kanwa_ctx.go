@@ -0,0 +1,185 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// posHints are the following characters which indicate the part-of-speech of a phrase.
+// There is no morphological analysis, thus the hints are heuristics on the particles and endings following the phrase.
+var posHints = map[string]*regexp.Regexp{
+	// noun followed by a case particle, a punctuation mark or the end of the text
+	"noun": regexp.MustCompile(`\A(?:[がをはのへとでもや、。]|$)`),
+	// adjectival noun (keiyoudoushi) followed by its endings, e.g. 上手な, 上手に, 上手だ
+	"na": regexp.MustCompile(`\A(?:な|に|だ|で|じゃ|です|さ)`),
+	// verbal noun followed by the forms of する, e.g. 勉強する, 勉強した
+	"suru": regexp.MustCompile(`\A(?:す|し|さ|せ)`),
+}
+
+// Condition is a compiled context token of a reading.
+//
+// The tokens have the following syntax, the preceding text being the text of the segment preceding the phrase
+// and the following text the characters after the phrase, up to the length of the longest phrase:
+//
+//	X       the preceding text equals X
+//	<X      the preceding text ends with X
+//	>X      the following text begins with X
+//	<~RE    the preceding text matches the regular expression RE at its end
+//	>~RE    the following text matches the regular expression RE at its beginning
+//	pos:P   the following text indicates the part-of-speech P: noun, na (adjectival noun) or suru (verbal noun)
+//	!T      the token T does not match
+//
+// Several conditions joined by "&" form a clause, which matches if all of them match, e.g. "<月&!>中", see Clause.
+type Condition struct {
+	negate    bool
+	following bool // condition on the following text, otherwise on the preceding text
+	exact     bool // the preceding text equals the literal
+	literal   string
+	re        *regexp.Regexp
+}
+
+// ParseCondition compiles a context token.
+func ParseCondition(token string) (Condition, error) {
+	var c Condition
+	token, c.negate = strings.CutPrefix(token, "!")
+
+	switch {
+	case len(token) == 0:
+		return Condition{}, fmt.Errorf("invalid context: empty condition")
+
+	case strings.HasPrefix(token, "pos:"):
+		re, ok := posHints[token[len("pos:"):]]
+		if !ok {
+			return Condition{}, fmt.Errorf("invalid context: unknown part-of-speech %q", token[len("pos:"):])
+		}
+
+		c.following, c.re = true, re
+
+	case strings.HasPrefix(token, "<~"), strings.HasPrefix(token, ">~"):
+		c.following = token[0] == '>'
+
+		pattern := `(?:` + token[2:] + `)\z`
+		if c.following {
+			pattern = `\A(?:` + token[2:] + `)`
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return Condition{}, fmt.Errorf("invalid context: %w", err)
+		}
+
+		c.re = re
+
+	case strings.HasPrefix(token, "<"), strings.HasPrefix(token, ">"):
+		if len(token) == 1 {
+			return Condition{}, fmt.Errorf("invalid context: empty condition %q", token)
+		}
+
+		c.following, c.literal = token[0] == '>', token[1:]
+
+	default:
+		c.exact, c.literal = true, token
+
+	}
+
+	return c, nil
+}
+
+// Match returns true if the condition matches the text preceding and the text following the phrase.
+func (c Condition) Match(preceding, following string) bool {
+	text := preceding
+	if c.following {
+		text = following
+	}
+
+	var ok bool
+	switch {
+	case c.re != nil:
+		ok = c.re.MatchString(text)
+
+	case c.exact:
+		ok = text == c.literal
+
+	case c.following:
+		ok = strings.HasPrefix(text, c.literal)
+
+	default:
+		ok = strings.HasSuffix(text, c.literal)
+
+	}
+
+	return ok != c.negate
+}
+
+// Clause is a compiled context token, i.e. a conjunction of the conditions joined by "&" in the token.
+type Clause []Condition
+
+// ParseClause compiles a context token, which joins one or several conditions by "&".
+func ParseClause(token string) (Clause, error) {
+	var c Clause
+	for _, part := range strings.Split(token, "&") {
+		cond, err := ParseCondition(part)
+		if err != nil {
+			return nil, err
+		}
+
+		c = append(c, cond)
+	}
+
+	return c, nil
+}
+
+// Rule is a compiled KanjiCtx.
+// A reading applies if its rule is empty or any of its clauses match, i.e. the tokens of a KanjiCtx are alternatives,
+// while the conditions of a token joined by "&" must match together.
+type Rule []Clause
+
+// Compile compiles the context tokens to a rule, see Condition for the syntax of the tokens.
+func (m KanjiCtx) Compile() (Rule, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	r := make(Rule, 0, len(m))
+	for _, token := range m {
+		c, err := ParseClause(token)
+		if err != nil {
+			return nil, fmt.Errorf("%w in %q", err, token)
+		}
+
+		r = append(r, c)
+	}
+
+	return r, nil
+}
+
+// Match returns true if the rule is empty or all conditions of any of its clauses match.
+// The following text is computed only if a condition depends on it.
+func (r Rule) Match(preceding string, following func() string) bool {
+	if len(r) == 0 {
+		return true
+	}
+
+	var next string
+	var computed bool
+	for _, clause := range r {
+		matched := true
+		for _, c := range clause {
+			if c.following && !computed {
+				next, computed = following(), true
+			}
+
+			if !c.Match(preceding, next) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
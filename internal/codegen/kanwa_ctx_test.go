@@ -0,0 +1,58 @@
+package codegen
+
+import (
+	"testing"
+)
+
+func TestKanjiCtx_Compile(t *testing.T) {
+	for _, tt := range []struct {
+		ctx                  KanjiCtx
+		preceding, following string
+		want                 bool
+		wantErr              bool
+	}{
+		{nil, "", "", true, false},
+		{KanjiCtx{"そん"}, "そん", "", true, false},
+		{KanjiCtx{"そん"}, "はそん", "", false, false},
+		{KanjiCtx{"<月"}, "五月", "", true, false},
+		{KanjiCtx{"<月"}, "五日", "", false, false},
+		{KanjiCtx{">を"}, "", "を行く", true, false},
+		{KanjiCtx{">を"}, "を", "な", false, false},
+		{KanjiCtx{"<~[0-9０-９]月"}, "十2月", "", true, false},
+		{KanjiCtx{"<~[0-9]"}, "2月", "", false, false},
+		{KanjiCtx{">~[をが]"}, "", "が", true, false},
+		{KanjiCtx{">~[をが]"}, "", "もが", false, false},
+		{KanjiCtx{"pos:noun"}, "", "", true, false},
+		{KanjiCtx{"pos:noun"}, "", "の", true, false},
+		{KanjiCtx{"pos:na"}, "", "な人", true, false},
+		{KanjiCtx{"pos:suru"}, "", "した", true, false},
+		{KanjiCtx{"!>中"}, "", "中", false, false},
+		{KanjiCtx{"!>中"}, "", "は", true, false},
+		{KanjiCtx{">中", "<月"}, "月", "は", true, false},
+		{KanjiCtx{"<月&!>中"}, "五月", "に", true, false},
+		{KanjiCtx{"<月&!>中"}, "五月", "中", false, false},
+		{KanjiCtx{"<月&!>中"}, "五日", "に", false, false},
+		{KanjiCtx{"<枚&>だ", ">を行"}, "一枚", "だ", true, false},
+		{KanjiCtx{"<枚&>だ", ">を行"}, "一枚", "に", false, false},
+		{KanjiCtx{"<枚&>だ", ">を行"}, "", "を行く", true, false},
+		{KanjiCtx{"<月&"}, "", "", false, true},
+		{KanjiCtx{"pos:verb"}, "", "", false, true},
+		{KanjiCtx{"<~("}, "", "", false, true},
+		{KanjiCtx{"<"}, "", "", false, true},
+		{KanjiCtx{"!"}, "", "", false, true},
+	} {
+		rule, err := tt.ctx.Compile()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("KanjiCtx(%q).Compile() error = %v, want error: %t", tt.ctx, err, tt.wantErr)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if got := rule.Match(tt.preceding, func() string { return tt.following }); got != tt.want {
+			t.Errorf("Rule(%q).Match(%q, %q) = %t, want %t", tt.ctx, tt.preceding, tt.following, got, tt.want)
+		}
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"unicode/utf8"
 
 	ordered "github.com/wk8/go-ordered-map/v2"
 )
@@ -50,6 +54,7 @@ var kanwaMapResources = map[string][]string{
 }
 
 // KanjiCtx is a list of contexts in which a kanji character or phrase is used.
+// Each context is a condition on the text surrounding the phrase, see Condition.
 type KanjiCtx []string
 
 func (m *KanjiCtx) Append(v ...string) *KanjiCtx {
@@ -72,19 +77,24 @@ func (m KanjiCtx) Contains(v string) bool {
 // yomi is the reading of the kanji character or phrase.
 // ctx is a list of contexts in which the kanji character or phrase is used.
 type KanjiCtxPair struct {
-	Yomi string   `json:"yomi"`
-	Ctx  KanjiCtx `json:"ctx"`
+	Yomi   string   `json:"yomi"`
+	Ctx    KanjiCtx `json:"ctx"`
+	Source string   `json:"source"` // name of the source dictionary, e.g. "kakasidict", empty if unknown
 }
 
 func (m *KanjiCtxPair) SetCtx(v KanjiCtx) *KanjiCtxPair { m.Ctx = v; return m }
 func (m *KanjiCtxPair) SetYomi(v string) *KanjiCtxPair  { m.Yomi = v; return m }
 
 func (m KanjiCtxPair) MarshalJSON() ([]byte, error) {
-	return json.Marshal([2]any{m.Yomi, m.Ctx})
+	if len(m.Source) == 0 {
+		return json.Marshal([2]any{m.Yomi, m.Ctx})
+	}
+
+	return json.Marshal([3]any{m.Yomi, m.Ctx, m.Source})
 }
 
 func (m *KanjiCtxPair) UnmarshalJSON(data []byte) error {
-	var a [2]any
+	var a [3]any
 	if err := json.Unmarshal(data, &a); err != nil {
 		return err
 	}
@@ -95,6 +105,7 @@ func (m *KanjiCtxPair) UnmarshalJSON(data []byte) error {
 		m.Ctx = append(m.Ctx, fmt.Sprint(c))
 	}
 
+	m.Source, _ = a[2].(string)
 	return nil
 }
 
@@ -139,6 +150,9 @@ func (m *KanjiCtxMap) UnmarshalJSON(data []byte) error {
 // Each rune represents a beginning of a kanji character or a phrase in the Japanese language.
 type KanwaMap ordered.OrderedMap[rune, KanjiCtxMap]
 
+// NewKanwaMap returns an empty kanwa map.
+func NewKanwaMap() *KanwaMap { return (*KanwaMap)(ordered.New[rune, KanjiCtxMap]()) }
+
 func (m KanwaMap) Get(k rune) KanjiCtxMap { return mapGet(ordered.OrderedMap[rune, KanjiCtxMap](m), k) }
 func (m KanwaMap) Has(k rune) bool        { return mapHas(ordered.OrderedMap[rune, KanjiCtxMap](m), k) }
 
@@ -149,21 +163,51 @@ func (m KanwaMap) Iter() func() (rune, KanjiCtxMap, bool) {
 func (m KanwaMap) Keys() []rune { return mapKeys(ordered.OrderedMap[rune, KanjiCtxMap](m)) }
 func (m KanwaMap) Len() int     { return mapLen(ordered.OrderedMap[rune, KanjiCtxMap](m)) }
 
+// MarshalBinary encodes the map in the binary format.
+// Each reading is stored as a record of the kanji phrase, the yomi, the index of its source and the contexts.
+// The names of the sources are stored in a record with an empty phrase, which precedes the readings.
+func (m KanwaMap) MarshalBinary() ([]byte, error) {
+	sources := []string{""}
+	indices := map[string]int{"": 0}
+
+	var records [][]string
+	iterator := m.Iter()
+	for _, table, ok := iterator(); ok; _, table, ok = iterator() {
+		tableIterator := table.Iter()
+		for k, pairs, ok := tableIterator(); ok; k, pairs, ok = tableIterator() {
+			for _, v := range pairs {
+				i, ok := indices[v.Source]
+				if !ok {
+					i, indices[v.Source], sources = len(sources), len(sources), append(sources, v.Source)
+				}
+
+				if i > 0xff {
+					return nil, fmt.Errorf("too many sources: %d", len(sources))
+				}
+
+				records = append(records, append([]string{k, v.Yomi, string([]byte{byte(i)})}, v.Ctx...))
+			}
+		}
+	}
+
+	return encodeTable(binaryKanwaMap, append([][]string{append([]string{""}, sources...)}, records...)), nil
+}
+
 func (m KanwaMap) MarshalJSON() ([]byte, error) {
 	return (*ordered.OrderedMap[rune, KanjiCtxMap])(&m).MarshalJSON()
 }
 
-// parseLine parses a line from a file and updates the kanwa map.
-// The line is expected to have the format "yomi kanji [ctx ...]".
-// The yomi is the reading of the kanji character or phrase.
-// The kanji is the kanji character or phrase.
-func (m KanwaMap) parseLine(line string) {
-	tokens := strings.Split(line, " ")
-	if len(tokens) < 2 {
-		return
-	}
+// Add adds a kanji character or phrase and its reading to the kanwa map.
+// A yomi ending with an ASCII letter denotes okurigana (e.g. "かk"):
+// the letter is removed and the phrase is added once for each kana of the letter's row in CLetters,
+// with the kana appended to both the kanji and the yomi.
+// The ctx is a list of contexts in which the kanji character or phrase is used.
+func (m KanwaMap) Add(kanji, yomi string, ctx ...string) {
+	m.AddFrom("", kanji, yomi, ctx...)
+}
 
-	yomi, kanji := tokens[0], tokens[1]
+// AddFrom adds a kanji character or phrase and its reading like Add, recording the name of its source dictionary.
+func (m KanwaMap) AddFrom(source, kanji, yomi string, ctx ...string) {
 	yomi_runes := []rune(yomi)
 
 	var tail []rune
@@ -172,40 +216,127 @@ func (m KanwaMap) parseLine(line string) {
 		yomi_runes = yomi_runes[: len(yomi_runes)-1 : len(yomi_runes)-1]
 	}
 
-	var token_ctx []string
-	if len(tokens) > 2 {
-		token_ctx = tokens[2:]
-	}
-
-	m.update(kanji, string(yomi_runes), string(tail), token_ctx...)
+	m.update(source, kanji, string(yomi_runes), string(tail), ctx...)
 }
 
 func (m *KanwaMap) Set(k rune, v KanjiCtxMap) *KanwaMap {
 	return (*KanwaMap)(mapSet((*ordered.OrderedMap[rune, KanjiCtxMap])(m), k, v))
 }
 
+// UnmarshalBinary decodes the map from the binary format.
+// The records of a phrase are consecutive, since the records are sorted by the phrase.
+func (m *KanwaMap) UnmarshalBinary(data []byte) error {
+	t, err := decodeTable(binaryKanwaMap, data)
+	if err != nil {
+		return err
+	}
+
+	o := ordered.New[rune, KanjiCtxMap]()
+	var table *ordered.OrderedMap[string, []KanjiCtxPair]
+	var first rune
+	var sources, record []string
+	for i := range t.len() {
+		record, err = t.record(i, record[:0])
+		if err != nil {
+			return err
+		}
+
+		if i == 0 {
+			if len(record) < 2 || len(record[0]) > 0 {
+				return fmt.Errorf("invalid kanwa map: missing sources")
+			}
+
+			sources = slices.Clone(record[1:])
+			continue
+		}
+
+		if len(record) < 3 || len(record[2]) != 1 || int(record[2][0]) >= len(sources) {
+			return fmt.Errorf("invalid kanwa map record: %q", record)
+		}
+
+		k := record[0]
+		if c, _ := utf8.DecodeRuneInString(k); table == nil || c != first {
+			table, first = ordered.New[string, []KanjiCtxPair](), c
+			o.Set(c, KanjiCtxMap(*table))
+		}
+
+		var ctx KanjiCtx
+		if len(record) > 3 {
+			ctx = append(ctx, record[3:]...)
+		}
+
+		pairs, _ := table.Get(k)
+		table.Set(k, append(pairs, KanjiCtxPair{Yomi: record[1], Ctx: ctx, Source: sources[record[2][0]]}))
+	}
+
+	*m = KanwaMap(*o)
+	return nil
+}
+
 func (m *KanwaMap) UnmarshalJSON(data []byte) error {
 	return (*ordered.OrderedMap[rune, KanjiCtxMap])(m).UnmarshalJSON(data)
 }
 
+// ParseKanwa reads a dictionary in the format of the kakasidict, i.e. lines of "yomi kanji [ctx ...]",
+// and calls add for each entry.
+// Like for the embedded dictionaries, empty lines and lines starting with ";;" are skipped and escape sequences are decoded.
+// Malformed lines and the errors returned by add are reported with their line numbers.
+func ParseKanwa(r io.Reader, add func(kanji, yomi string, ctx ...string) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for line := range traverseFile(ctx, r) {
+		if line.err != nil {
+			return line.err
+		}
+
+		yomi, kanji, token_ctx, ok := splitLine(line.text)
+		if !ok {
+			return fmt.Errorf("line %d: expected \"yomi kanji [ctx ...]\", got %q", line.num, line.text)
+		}
+
+		if err := add(kanji, yomi, token_ctx...); err != nil {
+			return fmt.Errorf("line %d: %w", line.num, err)
+		}
+	}
+
+	return nil
+}
+
+// splitLine splits a line of the format "yomi kanji [ctx ...]" into its fields.
+// It returns false if the line has less than two fields.
+func splitLine(line string) (yomi, kanji string, token_ctx []string, ok bool) {
+	tokens := strings.Split(line, " ")
+	if len(tokens) < 2 {
+		return "", "", nil, false
+	}
+
+	if len(tokens) > 2 {
+		token_ctx = tokens[2:]
+	}
+
+	return tokens[0], tokens[1], token_ctx, true
+}
+
 // update updates the kanwa map with a kanji character or phrase.
 // The kanji is the kanji character or phrase.
 // The yomi is the reading of the kanji character or phrase.
 // The tail is the last character of the reading.
+// The source is the name of the source dictionary.
 // The token_ctx is a list of contexts in which the kanji character or phrase is used.
-func (m KanwaMap) update(kanji, yomi, tail string, token_ctx ...string) {
+func (m KanwaMap) update(source, kanji, yomi, tail string, token_ctx ...string) {
 	if len(tail) == 0 {
 		kanji_runes := []rune(kanji)
 		c := kanji_runes[0]
 		if !m.Has(c) {
 			v := (*KanjiCtxMap)(ordered.New[string, []KanjiCtxPair]())
-			_ = v.Set(kanji, []KanjiCtxPair{{yomi, KanjiCtx(token_ctx)}})
+			_ = v.Set(kanji, []KanjiCtxPair{{Yomi: yomi, Ctx: KanjiCtx(token_ctx), Source: source}})
 			_ = m.Set(c, *v)
 			return
 		}
 
 		gotKanjiCtxMap := m.Get(c)
-		_ = gotKanjiCtxMap.Append(kanji, KanjiCtxPair{yomi, KanjiCtx(token_ctx)})
+		_ = gotKanjiCtxMap.Append(kanji, KanjiCtxPair{Yomi: yomi, Ctx: KanjiCtx(token_ctx), Source: source})
 		m.Set(c, gotKanjiCtxMap)
 		return
 	}
@@ -216,7 +347,7 @@ func (m KanwaMap) update(kanji, yomi, tail string, token_ctx ...string) {
 	}
 
 	for _, v := range got {
-		m.update(kanji+v, yomi+v, "", token_ctx...)
+		m.update(source, kanji+v, yomi+v, "", token_ctx...)
 	}
 }
 
@@ -226,12 +357,13 @@ func (m KanwaMap) update(kanji, yomi, tail string, token_ctx ...string) {
 // The yomi is the reading of the kanji character or phrase.
 // The kanji is the kanji character or phrase.
 // The ctx is a list of contexts in which the kanji character or phrase is used.
+// Source files named like "SKK-JISYO.L" are read in the format of the SKK-JISYO instead, see ParseSKK.
 func makeKanwaMap(src_list []string) (*KanwaMap, error) {
 	if err := verifyKanwaMapSourceList(src_list); err != nil {
 		return nil, err
 	}
 
-	m := (*KanwaMap)(ordered.New[rune, KanjiCtxMap]())
+	m := NewKanwaMap()
 	for _, src := range src_list {
 		f, err := os.OpenFile(src, os.O_RDONLY, os.ModePerm)
 		if err != nil {
@@ -240,11 +372,17 @@ func makeKanwaMap(src_list []string) (*KanwaMap, error) {
 
 		defer f.Close()
 
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+		source := strings.TrimSuffix(filepath.Base(src), filepath.Ext(src))
+		err = kanwaParserOf(src)(f, func(kanji, yomi string, ctx ...string) error {
+			if _, err := KanjiCtx(ctx).Compile(); err != nil {
+				return err
+			}
 
-		for line := range traverseFile(ctx, f) {
-			m.parseLine(line)
+			m.AddFrom(source, kanji, yomi, ctx...)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src, err)
 		}
 	}
 
@@ -0,0 +1,252 @@
+package codegen
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LexiconFormat is the layout of the columns of a MeCab lexicon CSV (e.g. lex.csv of UniDic).
+// The columns are numbered from 0.
+type LexiconFormat struct {
+	Surface int   // column of the surface form
+	Cost    int   // column of the word cost, words with lower costs are more frequent
+	Reading int   // column of the reading in katakana
+	POS     []int // columns of the part-of-speech, from the most general to the most specific
+
+	// Notice is the copyright and licence notice of the lexicon, which is copied into the header of the generated sources.
+	Notice []string
+}
+
+var (
+	// UniDicFormat is the layout of the lexicon CSV of UniDic 2.x and 3.x.
+	// The reading is the kana column, which unlike the pron column spells long vowels as in the orthography.
+	UniDicFormat = LexiconFormat{Surface: 0, Cost: 3, Reading: 24, POS: []int{4, 5, 6, 7}, Notice: []string{
+		"Copyright (c) 2011-2021, The UniDic Consortium",
+		"UniDic is licensed under the GPL v2.0, the LGPL v2.1 or the BSD 3-Clause License",
+	}}
+
+	// IPADicFormat is the layout of the lexicon CSV of IPAdic.
+	IPADicFormat = LexiconFormat{Surface: 0, Cost: 3, Reading: 11, POS: []int{4, 5, 6, 7}, Notice: []string{
+		"Copyright 2000, 2001, 2002, 2003 Nara Institute of Science and Technology. All Rights Reserved.",
+		"IPAdic is distributed under the license of the Nara Institute of Science and Technology, see COPYING of IPAdic",
+	}}
+)
+
+// lexiconResources is a map of the kanwa sources generated from a lexicon and the parts-of-speech of their words.
+var lexiconResources = map[string][][]string{
+	"unidict_noun.utf8": {{"名詞"}},
+	"unidict_adj.utf8":  {{"形容詞"}},
+}
+
+// lexiconAdjectiveTails are the tail letters of the stem of an adjective (e.g. "愛くるし"),
+// they cover the endings of "愛くるしい", "愛くるしく", "愛くるしかった", "愛くるしさ", ...
+var lexiconAdjectiveTails = []string{"i", "k", "s"}
+
+// lexiconWord is a word of a lexicon.
+// The yomi of an adjective is its stem, the tail is the okurigana tail letter.
+type lexiconWord struct {
+	kanji, yomi, tail string
+	cost              int
+	line              int
+}
+
+// ParseLexicon returns a parser of a lexicon CSV in the given format.
+// Only words with a part-of-speech beginning with one of the pos are passed (e.g. {"名詞", "固有名詞"}),
+// all words are passed if pos is empty.
+//
+// The words must begin with a kanji character and have a reading in katakana, which is converted to hiragana.
+// Adjectives (形容詞) are passed in their dictionary form as stems with okurigana tail letters
+// (e.g. "あいくるしk 愛くるし"), their other conjugated forms are skipped.
+// The words are passed in the order of their readings like in the kakasidict,
+// words with the same reading in the order of their costs, and duplicate words are passed once.
+func ParseLexicon(format LexiconFormat, pos ...[]string) KanwaParser {
+	return func(r io.Reader, add func(kanji, yomi string, ctx ...string) error) error {
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord, cr.LazyQuotes, cr.ReuseRecord = -1, true, true
+
+		columns := max(format.Surface, format.Cost, format.Reading, slices.Max(format.POS)) + 1
+
+		var words []lexiconWord
+		for {
+			record, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				return err
+			}
+
+			num, _ := cr.FieldPos(0)
+			if len(record) < columns {
+				return fmt.Errorf("line %d: expected at least %d columns, got %d", num, columns, len(record))
+			}
+
+			if !format.hasPOS(record, pos) {
+				continue
+			}
+
+			cost, err := strconv.Atoi(record[format.Cost])
+			if err != nil {
+				return fmt.Errorf("line %d: invalid cost: %w", num, err)
+			}
+
+			kanji, yomi, ok := lexiconReading(record[format.Surface], record[format.Reading])
+			if !ok {
+				continue
+			}
+
+			if record[format.POS[0]] != "形容詞" {
+				words = append(words, lexiconWord{kanji: kanji, yomi: yomi, cost: cost, line: num})
+				continue
+			}
+
+			kanji, kanjiOk := strings.CutSuffix(kanji, "い")
+			yomi, yomiOk := strings.CutSuffix(yomi, "い")
+			if !kanjiOk || !yomiOk || len(kanji) == 0 || len(yomi) == 0 {
+				continue
+			}
+
+			for _, tail := range lexiconAdjectiveTails {
+				words = append(words, lexiconWord{kanji: kanji, yomi: yomi, tail: tail, cost: cost, line: num})
+			}
+		}
+
+		slices.SortStableFunc(words, func(a, b lexiconWord) int {
+			return cmp.Or(strings.Compare(a.yomi, b.yomi), cmp.Compare(a.cost, b.cost))
+		})
+
+		seen := make(map[[2]string]bool, len(words))
+		for _, w := range words {
+			if seen[[2]string{w.kanji, w.yomi + w.tail}] {
+				continue
+			}
+
+			seen[[2]string{w.kanji, w.yomi + w.tail}] = true
+			if err := add(w.kanji, w.yomi+w.tail); err != nil {
+				return fmt.Errorf("line %d: %w", w.line, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// hasPOS returns true if the part-of-speech of the record begins with one of the pos, or if pos is empty.
+func (f LexiconFormat) hasPOS(record []string, pos [][]string) bool {
+	if len(pos) == 0 {
+		return true
+	}
+
+	for _, p := range pos {
+		if len(p) > len(f.POS) {
+			continue
+		}
+
+		ok := true
+		for i, v := range p {
+			ok = ok && record[f.POS[i]] == v
+		}
+
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lexiconReading returns the surface and the reading converted to hiragana.
+// It returns false if the surface does not begin with a kanji character or the reading is not katakana.
+func lexiconReading(surface, reading string) (string, string, bool) {
+	if first, _ := utf8.DecodeRuneInString(surface); !unicode.Is(unicode.Han, first) || strings.ContainsRune(surface, ' ') {
+		return "", "", false
+	}
+
+	if len(reading) == 0 {
+		return "", "", false
+	}
+
+	yomi := make([]rune, 0, len(reading))
+	for _, r := range reading {
+		switch {
+		case r >= 'ァ' && r <= 'ヶ':
+			yomi = append(yomi, r-'ァ'+'ぁ')
+
+		case r == 'ー':
+			yomi = append(yomi, r)
+
+		default:
+			return "", "", false
+
+		}
+	}
+
+	return surface, string(yomi), true
+}
+
+// GenerateKanwaSources generates the kanwa sources distilled from a lexicon CSV in the given format,
+// i.e. the unidict files of the nouns and the adjectives, and writes them to the dst directory.
+// The sources are in the format of the kakasidict and are read by Generate like the other kanwa sources.
+// The header of the sources names the lexicon by name (e.g. "unidic v3.1.0"), or by its file name if name is empty.
+func GenerateKanwaSources(dst, lexicon, name string, format LexiconFormat) error {
+	if len(name) == 0 {
+		name = filepath.Base(lexicon)
+	}
+
+	for tgt, pos := range lexiconResources {
+		f, err := os.Open(lexicon)
+		if err != nil {
+			return err
+		}
+
+		err = dumpKanwaSource(filepath.Join(dst, tgt), name, format.Notice, f, ParseLexicon(format, pos...))
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", lexicon, err)
+		}
+	}
+
+	return nil
+}
+
+// dumpKanwaSource writes the entries read by the parser to a file in the format of the kakasidict.
+// The header names the source and holds its notice.
+// The file will be created if it doesn't exist, and truncated if it does.
+// The directory structure will be created if it doesn't exist a priori.
+func dumpKanwaSource(dst, name string, notice []string, r io.Reader, parse KanwaParser) error {
+	_ = os.MkdirAll(filepath.Dir(dst), os.ModePerm)
+	o, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	defer o.Close()
+
+	w := bufio.NewWriter(o)
+	_, _ = fmt.Fprintf(w, ";; KAKASI (Kanji Kana Simple inversion program)\n;; unidict - dictionary distilled from %s\n", name)
+	for _, line := range notice {
+		_, _ = fmt.Fprintf(w, ";; %s\n", line)
+	}
+
+	err = parse(r, func(kanji, yomi string, ctx ...string) error {
+		_, err := fmt.Fprintln(w, strings.Join(append([]string{yomi, kanji}, ctx...), " "))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
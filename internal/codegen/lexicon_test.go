@@ -0,0 +1,106 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testUniDicLexicon = `愛くるしい,12,12,6000,形容詞,一般,*,*,形容詞,終止形-一般,アイクルシイ,愛くるしい,愛くるしい,アイクルシー,愛くるしい,アイクルシー,和,*,*,*,*,*,*,用,アイクルシイ,アイクルシイ,アイクルシイ,アイクルシイ,4,C1,*,1,2
+愛くるしく,13,13,6000,形容詞,一般,*,*,形容詞,連用形-一般,アイクルシイ,愛くるしい,愛くるしく,アイクルシク,愛くるしい,アイクルシー,和,*,*,*,*,*,*,用,アイクルシク,アイクルシイ,アイクルシク,アイクルシイ,4,C1,*,1,2
+東京,1,1,3000,名詞,固有名詞,地名,一般,*,*,トウキョウ,トウキョウ,東京,トーキョー,東京,トーキョー,固,*,*,*,*,*,*,地名,トウキョウ,トウキョウ,トウキョウ,トウキョウ,0,*,*,3,4
+間,2,2,5000,名詞,普通名詞,一般,*,*,*,マ,間,間,マ,間,マ,和,*,*,*,*,*,*,体,マ,マ,マ,マ,1,C3,*,5,6
+間,2,2,4000,名詞,普通名詞,副詞可能,*,*,*,アイダ,間,間,アイダ,間,アイダ,和,*,*,*,*,*,*,体,アイダ,アイダ,アイダ,アイダ,0,C2,*,7,8
+間,2,2,4500,名詞,普通名詞,副詞可能,*,*,*,アイダ,間,間,アイダ,間,アイダ,和,*,*,*,*,*,*,体,アイダ,アイダ,アイダ,アイダ,0,C2,*,7,8
+お茶,3,3,4000,名詞,普通名詞,一般,*,*,*,チャ,茶,お茶,オチャ,お茶,オチャ,和,*,*,*,*,*,*,体,オチャ,オチャ,オチャ,オチャ,0,C2,*,9,10
+ＣＰＵ,4,4,4000,名詞,普通名詞,一般,*,*,*,シーピーユー,ＣＰＵ,ＣＰＵ,シーピーユー,ＣＰＵ,シーピーユー,外,*,*,*,*,*,*,体,シーピーユー,シーピーユー,シーピーユー,シーピーユー,3,C1,*,11,12
+走る,5,5,4000,動詞,一般,*,*,五段-ラ行,終止形-一般,ハシル,走る,走る,ハシル,走る,ハシル,和,*,*,*,*,*,*,用,ハシル,ハシル,ハシル,ハシル,2,C1,*,13,14
+`
+
+const testIPADicLexicon = `愛くるしい,43,43,6000,形容詞,自立,*,*,形容詞・イ段,基本形,愛くるしい,アイクルシイ,アイクルシイ
+東京,1293,1293,3000,名詞,固有名詞,地域,一般,*,*,東京,トウキョウ,トーキョー
+走る,772,772,4000,動詞,自立,*,*,五段・ラ行,基本形,走る,ハシル,ハシル
+`
+
+func TestParseLexicon(t *testing.T) {
+	type entry struct{ kanji, yomi string }
+
+	for _, tt := range []struct {
+		name    string
+		format  LexiconFormat
+		pos     [][]string
+		args    string
+		want    []entry
+		wantErr string
+	}{
+		{"unidic nouns", UniDicFormat, [][]string{{"名詞"}}, testUniDicLexicon, []entry{
+			{"間", "あいだ"}, {"東京", "とうきょう"}, {"間", "ま"},
+		}, ""},
+		{"unidic proper nouns", UniDicFormat, [][]string{{"名詞", "固有名詞"}}, testUniDicLexicon, []entry{
+			{"東京", "とうきょう"},
+		}, ""},
+		{"unidic adjectives", UniDicFormat, [][]string{{"形容詞"}}, testUniDicLexicon, []entry{
+			{"愛くるし", "あいくるしi"}, {"愛くるし", "あいくるしk"}, {"愛くるし", "あいくるしs"},
+		}, ""},
+		{"ipadic", IPADicFormat, nil, testIPADicLexicon, []entry{
+			{"愛くるし", "あいくるしi"}, {"愛くるし", "あいくるしk"}, {"愛くるし", "あいくるしs"},
+			{"東京", "とうきょう"}, {"走る", "はしる"},
+		}, ""},
+		{"columns", IPADicFormat, nil, "東京,1293,1293,3000,名詞\n", nil, "line 1"},
+		{"cost", IPADicFormat, nil, testIPADicLexicon + "東京,1,1,x,名詞,固有名詞,地域,一般,*,*,東京,トウキョウ,トーキョー\n", nil, "line 4"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []entry
+			err := ParseLexicon(tt.format, tt.pos...)(strings.NewReader(tt.args), func(kanji, yomi string, ctx ...string) error {
+				got = append(got, entry{kanji, yomi})
+				return nil
+			})
+			if (err == nil) != (tt.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("ParseLexicon() error = %v, want %q", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLexicon() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateKanwaSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	lexicon := filepath.Join(tmpDir, "lex.csv")
+	if err := os.WriteFile(lexicon, []byte(testUniDicLexicon), os.ModePerm); err != nil {
+		t.Errorf("os.WriteFile() error = %v", err)
+		return
+	}
+
+	if err := GenerateKanwaSources(tmpDir, lexicon, "unidic v3.1.0", UniDicFormat); err != nil {
+		t.Errorf("GenerateKanwaSources() error = %v", err)
+		return
+	}
+
+	for tgt, want := range map[string]string{
+		"unidict_noun.utf8": "あいだ 間\nとうきょう 東京\nま 間\n",
+		"unidict_adj.utf8":  "あいくるしi 愛くるし\nあいくるしk 愛くるし\nあいくるしs 愛くるし\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(tmpDir, tgt))
+		if err != nil {
+			t.Errorf("os.ReadFile() error = %v", err)
+			continue
+		}
+
+		if got := string(data); !strings.HasPrefix(got, ";; KAKASI") || !strings.HasSuffix(got, "\n"+want) {
+			t.Errorf("GenerateKanwaSources() wrote %s:\n%s\nwant entries:\n%s", tgt, got, want)
+		}
+
+		if name := ";; unidict - dictionary distilled from unidic v3.1.0\n"; !strings.Contains(string(data), name) {
+			t.Errorf("GenerateKanwaSources() wrote %s without the name %q", tgt, name)
+		}
+
+		if notice := ";; " + UniDicFormat.Notice[0] + "\n"; !strings.Contains(string(data), notice) {
+			t.Errorf("GenerateKanwaSources() wrote %s without the notice %q", tgt, notice)
+		}
+	}
+}
@@ -17,6 +17,8 @@ var lookupMapResources = map[string]string{
 	"hepburnhira3.json":  "data/hepburnhira.utf8",
 	"kunreidict3.json":   "data/kunreidict.utf8",
 	"kunreihira3.json":   "data/kunreihira.utf8",
+	"nihondict3.json":    "data/nihondict.utf8",
+	"nihonhira3.json":    "data/nihonhira.utf8",
 	"passportdict3.json": "data/passportdict.utf8",
 	"passporthira3.json": "data/passporthira.utf8",
 }
@@ -35,6 +37,16 @@ func (m LookupMap) Iter() func() (string, string, bool) {
 func (m LookupMap) Keys() []string { return mapKeys(ordered.OrderedMap[string, string](m)) }
 func (m LookupMap) Len() int       { return mapLen(ordered.OrderedMap[string, string](m)) }
 
+func (m LookupMap) MarshalBinary() ([]byte, error) {
+	var records [][]string
+	iterator := m.Iter()
+	for k, v, ok := iterator(); ok; k, v, ok = iterator() {
+		records = append(records, []string{k, v})
+	}
+
+	return encodeTable(binaryLookupMap, records), nil
+}
+
 func (m LookupMap) MarshalJSON() ([]byte, error) {
 	return (*ordered.OrderedMap[string, string])(&m).MarshalJSON()
 }
@@ -62,6 +74,30 @@ func (m *LookupMap) Set(k, v string) *LookupMap {
 	return (*LookupMap)(mapSet((*ordered.OrderedMap[string, string])(m), k, v))
 }
 
+func (m *LookupMap) UnmarshalBinary(data []byte) error {
+	t, err := decodeTable(binaryLookupMap, data)
+	if err != nil {
+		return err
+	}
+
+	o := ordered.New[string, string](t.len())
+	for i := range t.len() {
+		record, err := t.record(i, nil)
+		if err != nil {
+			return err
+		}
+
+		if len(record) != 2 {
+			return fmt.Errorf("invalid lookup map record: %q", record)
+		}
+
+		o.Set(record[0], record[1])
+	}
+
+	*m = LookupMap(*o)
+	return nil
+}
+
 func (m *LookupMap) UnmarshalJSON(data []byte) error {
 	return (*ordered.OrderedMap[string, string])(m).UnmarshalJSON(data)
 }
@@ -86,7 +122,11 @@ func makeLookupMap(src string) (*LookupMap, error) {
 
 	m := (*LookupMap)(ordered.New[string, string]())
 	for line := range traverseFile(ctx, f) {
-		v, k, _ := strings.Cut(line, " ")
+		if line.err != nil {
+			return nil, line.err
+		}
+
+		v, k, _ := strings.Cut(line.text, " ")
 		m.Set(k, v)
 	}
 
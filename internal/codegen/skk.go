@@ -0,0 +1,112 @@
+package codegen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// KanwaParser reads a dictionary and calls add for each of its entries.
+// The kanji, yomi and ctx of an entry are passed like to (KanwaMap).Add.
+type KanwaParser func(r io.Reader, add func(kanji, yomi string, ctx ...string) error) error
+
+// ParseSKK reads a dictionary in the format of the SKK-JISYO, i.e. lines of "yomi /candidate1/candidate2;annotation/",
+// and calls add for each candidate.
+// The dictionary must be encoded in UTF-8, e.g. the SKK-JISYO files distributed in EUC-JP have to be converted first,
+// lines which are not valid UTF-8 are reported as errors. Lines starting with ";" are comments.
+//
+// The annotations of the candidates are stripped.
+// The yomi of an okuri-ari entry ends with the letter of its okurigana (e.g. "おくr"),
+// thus it is passed as is and expanded with CLetters by (KanwaMap).Add.
+// Entries which are no readings of kanji are skipped, i.e. entries with a yomi which is not hiragana
+// (e.g. abbreviations, numeric and prefix entries), okuri-ari entries with a letter not found in CLetters,
+// candidates not beginning with a kanji character and candidates which are Lisp expressions.
+// The okurigana blocks of okuri-ari entries (e.g. "[る/送/]") are skipped, since they repeat the candidates.
+// Malformed lines and the errors returned by add are reported with their line numbers.
+func ParseSKK(r io.Reader, add func(kanji, yomi string, ctx ...string) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1024*1024) // the entries of common kanji are long
+
+	for num := 1; sc.Scan(); num++ {
+		text := strings.TrimSpace(sc.Text())
+		if !utf8.ValidString(text) {
+			return fmt.Errorf("line %d: invalid UTF-8, the dictionary must be converted to UTF-8, e.g. from EUC-JP", num)
+		}
+
+		if len(text) == 0 || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		yomi, candidates, ok := strings.Cut(text, " /")
+		if !ok || !strings.HasSuffix(candidates, "/") {
+			return fmt.Errorf("line %d: expected \"yomi /candidate/...\", got %q", num, text)
+		}
+
+		if !isSKKYomi(yomi) {
+			continue
+		}
+
+		block := false
+		for _, candidate := range strings.Split(strings.TrimSuffix(candidates, "/"), "/") {
+			switch {
+			case strings.HasPrefix(candidate, "["):
+				block = true
+				continue
+
+			case block:
+				block = !strings.HasPrefix(candidate, "]")
+				continue
+
+			}
+
+			candidate, _, _ = strings.Cut(candidate, ";")
+			if first, _ := utf8.DecodeRuneInString(candidate); !unicode.Is(unicode.Han, first) {
+				continue
+			}
+
+			if err := add(candidate, yomi); err != nil {
+				return fmt.Errorf("line %d: %w", num, err)
+			}
+		}
+	}
+
+	return sc.Err()
+}
+
+// isSKKYomi returns true if the yomi of an SKK entry is a reading of kanji,
+// i.e. hiragana optionally followed by the letter of the okurigana.
+func isSKKYomi(yomi string) bool {
+	if last, size := utf8.DecodeLastRuneInString(yomi); last <= 'z' {
+		if _, ok := CLetters[last]; !ok {
+			return false
+		}
+
+		yomi = yomi[:len(yomi)-size]
+	}
+
+	if len(yomi) == 0 {
+		return false
+	}
+
+	for _, r := range yomi {
+		if !unicode.Is(unicode.Hiragana, r) && r != 'ー' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// kanwaParserOf returns the parser of a source file of the kanwa map.
+// SKK dictionaries are recognized by their names (e.g. "SKK-JISYO.L"), other files are read as kakasidict.
+func kanwaParserOf(src string) KanwaParser {
+	if strings.HasPrefix(filepath.Base(src), "SKK-JISYO") {
+		return ParseSKK
+	}
+
+	return ParseKanwa
+}
@@ -0,0 +1,60 @@
+package codegen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSKK(t *testing.T) {
+	type entry struct{ kanji, yomi string }
+
+	for _, tt := range []struct {
+		name    string
+		args    string
+		want    []entry
+		wantErr string
+	}{
+		{"comments", ";; -*- coding: utf-8 -*-\n;; okuri-ari entries.\n\n;; okuri-nasi entries.\n", nil, ""},
+		{"okuri-nasi", "かんじ /漢字/感じ;feeling/幹事/\n", []entry{{"漢字", "かんじ"}, {"感じ", "かんじ"}, {"幹事", "かんじ"}}, ""},
+		{"okuri-ari", "おくr /送/贈;gift/[る/送/贈/]/[り/送/]/\n", []entry{{"送", "おくr"}, {"贈", "おくr"}}, ""},
+		{"skipped candidates", "かな /仮名/カナ/(concat \"\\057\")/\n", []entry{{"仮名", "かな"}}, ""},
+		{"skipped entries", "cpu /中央処理装置/\nだい# /第#1/\nお> /御/\nあx /亜/\n", nil, ""},
+		{"malformed", "かんじ /漢字/\nかんじ 漢字\n", []entry{{"漢字", "かんじ"}}, "line 2"},
+		{"EUC-JP", "かんじ /漢字/\n\xa4\xab\xa4\xf3\xa4\xb8 /\xb4\xc1\xbb\xfa/\n", []entry{{"漢字", "かんじ"}}, "line 2: invalid UTF-8"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []entry
+			err := ParseSKK(strings.NewReader(tt.args), func(kanji, yomi string, ctx ...string) error {
+				got = append(got, entry{kanji, yomi})
+				return nil
+			})
+			if (err == nil) != (tt.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("ParseSKK() error = %v, want %q", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSKK() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSKK_KanwaMap(t *testing.T) {
+	m := NewKanwaMap()
+	err := ParseSKK(strings.NewReader("おくr /送/\n"), func(kanji, yomi string, ctx ...string) error {
+		m.Add(kanji, yomi, ctx...)
+		return nil
+	})
+	if err != nil {
+		t.Errorf("ParseSKK() error = %v", err)
+		return
+	}
+
+	table := m.Get('送')
+	for _, k := range []string{"送ら", "送り", "送る", "送れ", "送ろ"} {
+		if pairs := table.Get(k); len(pairs) != 1 || pairs[0].Yomi != "おく"+strings.TrimPrefix(k, "送") {
+			t.Errorf("KanwaMap.Get(%q) = %v, want reading %q", k, pairs, "おく"+strings.TrimPrefix(k, "送"))
+		}
+	}
+}
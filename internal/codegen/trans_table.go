@@ -32,6 +32,23 @@ func (m TransTable) Get(c rune) string {
 func (m TransTable) Keys() []rune { return mapKeys(ordered.OrderedMap[rune, *string](m)) }
 func (m TransTable) Len() int     { return mapLen(ordered.OrderedMap[rune, *string](m)) }
 
+// MarshalBinary encodes the table in the binary format.
+// Runes mapped to nil are stored without a value.
+func (m TransTable) MarshalBinary() ([]byte, error) {
+	var records [][]string
+	iterator := m.Iter()
+	for k, v, ok := iterator(); ok; k, v, ok = iterator() {
+		if v == nil {
+			records = append(records, []string{string(k)})
+			continue
+		}
+
+		records = append(records, []string{string(k), *v})
+	}
+
+	return encodeTable(binaryTransTable, records), nil
+}
+
 func (m TransTable) MarshalJSON() ([]byte, error) {
 	return (*ordered.OrderedMap[rune, *string])(&m).MarshalJSON()
 }
@@ -56,6 +73,44 @@ func (m *TransTable) spoof(lo, hi int64) {
 	}
 }
 
+func (m *TransTable) UnmarshalBinary(data []byte) error {
+	t, err := decodeTable(binaryTransTable, data)
+	if err != nil {
+		return err
+	}
+
+	o := ordered.New[rune, *string](t.len())
+	for i := range t.len() {
+		record, err := t.record(i, nil)
+		if err != nil {
+			return err
+		}
+
+		var k []rune
+		if len(record) > 0 {
+			k = []rune(record[0])
+		}
+
+		switch {
+		case len(k) != 1:
+			return fmt.Errorf("invalid translation table record: %q", record)
+
+		case len(record) == 1:
+			o.Set(k[0], nil)
+
+		case len(record) == 2:
+			o.Set(k[0], &record[1])
+
+		default:
+			return fmt.Errorf("invalid translation table record: %q", record)
+
+		}
+	}
+
+	*m = TransTable(*o)
+	return nil
+}
+
 func (m *TransTable) UnmarshalJSON(data []byte) error {
 	return (*ordered.OrderedMap[rune, *string])(m).UnmarshalJSON(data)
 }
@@ -80,9 +135,13 @@ func makeTransTable(src string) (*TransTable, error) {
 
 	m := (*TransTable)(ordered.New[rune, *string]())
 	for line := range traverseFile(ctx, f) {
-		v, k, ok := strings.Cut(line, " ")
+		if line.err != nil {
+			return nil, line.err
+		}
+
+		v, k, ok := strings.Cut(line.text, " ")
 		if !ok {
-			return nil, fmt.Errorf("invalid line: %s", line)
+			return nil, fmt.Errorf("invalid line: %s", line.text)
 		}
 
 		if l := len([]rune(k)); l > 1 || l == 0 {
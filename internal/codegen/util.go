@@ -3,6 +3,8 @@ package codegen
 import (
 	"bufio"
 	"context"
+	"encoding"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -75,6 +77,20 @@ func deref[T any](v *T) T {
 	return *v
 }
 
+// dumpBinary writes a value to a file in the binary format.
+// The file name is derived from the JSON file name by replacing the extension with ".bin".
+// The file will be created if it doesn't exist, and truncated if it does.
+// The directory structure will be created if it doesn't exist a priori.
+func dumpBinary(dst string, v encoding.BinaryMarshaler) error {
+	data, err := v.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_ = os.MkdirAll(filepath.Dir(dst), os.ModePerm)
+	return os.WriteFile(strings.TrimSuffix(dst, filepath.Ext(dst))+".bin", data, os.ModePerm)
+}
+
 // dump writes a value to a file in JSON format.
 // The file will be created if it doesn't exist, and truncated if it does.
 // The directory structure will be created if it doesn't exist a priori.
@@ -140,37 +156,54 @@ func mapSet[K comparable, T any](m *ordered.OrderedMap[K, T], k K, v T) *ordered
 	return m
 }
 
+// line is a line read by traverseFile.
+// The num is the line number in the file, starting at 1.
+// The err is set for the last line sent, if reading the file failed.
+type line struct {
+	num  int
+	text string
+	err  error
+}
+
 // traverseFile reads a file line by line and sends each line to a channel.
 // Empty lines and lines starting with ";;" are ignored.
 // The function returns a channel that will be closed when the file has been fully read.
 // It also takes a context that can be used to cancel the operation.
-func traverseFile(ctx context.Context, in *os.File) <-chan string {
+func traverseFile(ctx context.Context, in io.Reader) <-chan line {
 	sc := bufio.NewScanner(in)
 	sc.Split(bufio.ScanLines)
-	lines := make(chan string, 1)
+	lines := make(chan line, 1)
 
-	go func(sc *bufio.Scanner, lines chan<- string) {
-		for sc.Scan() {
+	go func(sc *bufio.Scanner, lines chan<- line) {
+		defer close(lines)
+
+		send := func(l line) bool {
 			select {
 
 			case <-ctx.Done():
-				close(lines)
-				return
-
-			default:
-				switch line := strings.TrimSpace(sc.Text()); {
+				return false
 
-				case len(line) == 0, strings.HasPrefix(line, ";;"):
-					continue
+			case lines <- l:
+				return true
 
-				default:
-					lines <- decodeEscapes(line)
-				}
 			}
+		}
+
+		for num := 1; sc.Scan(); num++ {
+			switch text := strings.TrimSpace(sc.Text()); {
 
+			case len(text) == 0, strings.HasPrefix(text, ";;"):
+				continue
+
+			case !send(line{num: num, text: decodeEscapes(text)}):
+				return
+
+			}
 		}
 
-		close(lines)
+		if err := sc.Err(); err != nil {
+			_ = send(line{err: err})
+		}
 	}(sc, lines)
 
 	return lines
@@ -0,0 +1,94 @@
+package kanji
+
+import (
+	"fmt"
+	"slices"
+)
+
+const (
+	SourceKanwa Source = "kanwa" // embedded kanwa dictionary
+	SourceUser  Source = "user"  // user dictionary of the converter
+)
+
+// Source is the dictionary a reading is looked up in.
+type Source string
+
+// Candidate is a reading of a kanji phrase at the beginning of a text.
+type Candidate struct {
+	Orig    string `json:"orig"`    // kanji phrase of the text
+	Yomi    string `json:"yomi"`    // reading in hiragana
+	Length  int    `json:"length"`  // length of the phrase in characters of the text
+	Source  Source `json:"source"`  // dictionary of the reading
+	Context bool   `json:"context"` // reading restricted to a context matched by the preceding text
+	Rank    int    `json:"rank"`    // rank of the reading, 0 for the reading chosen by Convert
+}
+
+// Candidates returns the readings of all kanji phrases at the beginning of the input text, ranked like by Convert:
+// the readings of longer phrases first, the user dictionary before the kanwa dictionary,
+// and the readings of a phrase in the order of the dictionary.
+// The bText is the text preceding the input text, which is used to match the context of the kanji phrases.
+func (j *JConv) Candidates(iText, bText string) ([]Candidate, error) {
+	text := j.itaiji.Convert(iText)
+	if len(text) == 0 {
+		return nil, fmt.Errorf("input text is empty")
+	}
+
+	iRunes, runes := []rune(iText), []rune(text)
+
+	user, err := j.user.Load().dict()
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Candidate
+	if user != nil {
+		candidates = user.Candidates(runes, bText, SourceUser)
+	}
+
+	candidates = append(candidates, j.kanwa.Candidates(runes, bText, SourceKanwa)...)
+
+	// the user dictionary precedes the kanwa dictionary for phrases of the same length
+	slices.SortStableFunc(candidates, func(a, b Candidate) int { return b.Length - a.Length })
+
+	// a reading of the user dictionary replaces the same reading of the kanwa dictionary
+	type key struct {
+		yomi   string
+		length int
+	}
+
+	seen := make(map[key]bool, len(candidates))
+	ranked := candidates[:0]
+	for _, c := range candidates {
+		if seen[key{c.Yomi, c.Length}] {
+			continue
+		}
+
+		seen[key{c.Yomi, c.Length}] = true
+		c.Length = j.inputLength(iRunes, runes, c.Length)
+		c.Orig, c.Rank = string(iRunes[:c.Length]), len(ranked)
+		ranked = append(ranked, c)
+	}
+
+	return ranked, nil
+}
+
+// Readings returns the distinct readings of the kanji character as a phrase of its own regardless of their contexts,
+// those of the user dictionary first.
+func (j *JConv) Readings(ch rune) []string {
+	dicts := []*Kanwa{j.kanwa}
+	if user, _ := j.user.Load().dict(); user != nil {
+		dicts = []*Kanwa{user, j.kanwa}
+	}
+
+	var readings []string
+	phrase := []rune(j.itaiji.Convert(string(ch)))
+	for _, dict := range dicts {
+		for _, v := range dict.trie.find(phrase) {
+			if !slices.Contains(readings, v.yomi) {
+				readings = append(readings, v.yomi)
+			}
+		}
+	}
+
+	return readings
+}
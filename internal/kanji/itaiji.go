@@ -2,7 +2,6 @@ package kanji
 
 import (
 	"strings"
-	"sync"
 
 	"github.com/sarumaj/go-kakasi/internal/codegen"
 	"github.com/sarumaj/go-kakasi/internal/properties"
@@ -10,35 +9,19 @@ import (
 
 // Itaiji is a type that represents a map of Itaiji characters.
 // It is used to convert Itaiji characters to their original form.
+// It is immutable, thus safe for concurrent use.
 type Itaiji struct {
-	sync.Mutex
-	table *codegen.TransTable
+	table    *codegen.TransTable
+	replacer *strings.Replacer
 }
 
 // Convert converts Itaiji characters to their original form.
 func (t *Itaiji) Convert(s string) string {
-	t.Lock()
-	defer t.Unlock()
-
-	var replacements []string
-	iterator := t.table.Iter()
-	for k, v, ok := iterator(); ok; k, v, ok = iterator() {
-		if v == nil {
-			replacements = append(replacements, string(k), "")
-			continue
-		}
-
-		replacements = append(replacements, string(k), *v)
-	}
-
-	return strings.NewReplacer(replacements...).Replace(s)
+	return t.replacer.Replace(s)
 }
 
 // HasKey returns true if the given key exists in the Itaiji map.
 func (t *Itaiji) HasKey(key rune) bool {
-	t.Lock()
-	defer t.Unlock()
-
 	return t.table.Has(key)
 }
 
@@ -49,5 +32,21 @@ func NewItaiji() (*Itaiji, error) {
 		return nil, err
 	}
 
-	return &Itaiji{table: t}, nil
+	return &Itaiji{table: t, replacer: newItaijiReplacer(t)}, nil
+}
+
+// newItaijiReplacer returns a replacer of the Itaiji characters of the table.
+func newItaijiReplacer(t *codegen.TransTable) *strings.Replacer {
+	var replacements []string
+	iterator := t.Iter()
+	for k, v, ok := iterator(); ok; k, v, ok = iterator() {
+		if v == nil {
+			replacements = append(replacements, string(k), "")
+			continue
+		}
+
+		replacements = append(replacements, string(k), *v)
+	}
+
+	return strings.NewReplacer(replacements...)
 }
@@ -0,0 +1,61 @@
+package kanji
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/sarumaj/go-kakasi/internal/codegen"
+)
+
+// lockedItaiji is the Itaiji converter building the replacer on every call under a mutex.
+// It serves as the reference for the precompiled replacer.
+type lockedItaiji struct {
+	sync.Mutex
+	table *codegen.TransTable
+}
+
+func (t *lockedItaiji) Convert(s string) string {
+	t.Lock()
+	defer t.Unlock()
+
+	return newItaijiReplacer(t.table).Replace(s)
+}
+
+func TestItaijiConvert(t *testing.T) {
+	itaiji, err := NewItaiji()
+	if err != nil {
+		t.Errorf("NewItaiji() error: %v", err)
+		return
+	}
+
+	reference := &lockedItaiji{table: itaiji.table}
+	for _, args := range []string{"", testConstitution, "髙橋さんの﨑", string(itaiji.table.Keys())} {
+		if got, want := itaiji.Convert(args), reference.Convert(args); got != want {
+			t.Errorf("(*Itaiji).Convert(%q) = %q, want %q", args, got, want)
+		}
+	}
+}
+
+func BenchmarkItaijiConvertParallel(b *testing.B) {
+	itaiji, err := NewItaiji()
+	if err != nil {
+		b.Fatalf("NewItaiji() error: %v", err)
+	}
+
+	windows := lookupWindows(testConstitution, 8)
+	for _, bb := range []struct {
+		name   string
+		itaiji interface{ Convert(string) string }
+	}{
+		{"precompiled", itaiji},
+		{"locked", &lockedItaiji{table: itaiji.table}},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					_ = bb.itaiji.Convert(string(windows[i%len(windows)]))
+				}
+			})
+		})
+	}
+}
@@ -2,8 +2,8 @@ package kanji
 
 import (
 	"fmt"
-
-	lru "github.com/hashicorp/golang-lru/v2"
+	"sync"
+	"sync/atomic"
 
 	"github.com/sarumaj/go-kakasi/internal/codegen"
 )
@@ -11,85 +11,103 @@ import (
 // JConv is a type that represents a Japanese text converter.
 // It is used to convert Japanese text to yomi reading.
 // It is based on Original KAKASI's EUC_JP - alphabet converter table.
+// The user dictionary can be updated while other goroutines are converting.
 type JConv struct {
-	cache  *lru.Cache[string, string]
-	kanwa  *Kanwa
-	itaiji *Itaiji
+	kanwa     *Kanwa
+	itaiji    *Itaiji
+	cacheSize int
+	mu        sync.Mutex               // serializes the updates of the user dictionary
+	user      atomic.Pointer[userDict] // user dictionary and conversion cache
+}
+
+// Match is the reading of the longest kanji phrase at the beginning of a text.
+type Match struct {
+	Yomi      string
+	Length    int  // length of the phrase in characters of the text
+	Ambiguous bool // the phrase has several readings in the dictionary of the reading, which apply to the preceding text
 }
 
 // Convert converts the input text to the yomi reading.
-func (j *JConv) Convert(iText, bText string) (string, int, error) {
-	var converted string
-	var max_length int
+// It returns the reading of the longest kanji phrase at the beginning of the input text and its length in characters.
+// The bText is the text preceding the input text, which is used to match the context of the kanji phrase.
+func (j *JConv) Convert(iText, bText string) (Match, error) {
+	user := j.user.Load()
 
 	// check if the conversion is already cached
-	cached, ok := j.cache.Get(iText + ":" + bText)
-	if ok {
-		if _, err := fmt.Sscanf(cached, "%s:%d", &converted, &max_length); err == nil {
-			return converted, max_length, nil
+	if user.cache != nil {
+		if cached, ok := user.cache.Get(iText + ":" + bText); ok {
+			return cached, nil
 		}
 	}
 
 	// convert itaiji characters to their original form
 	text := j.itaiji.Convert(iText)
-	if len([]rune(text)) == 0 {
-		return "", 0, fmt.Errorf("input text is empty")
+	if len(text) == 0 {
+		return Match{}, fmt.Errorf("input text is empty")
 	}
 
-	// calculate the number of changed characters
-	num_changed_ch := len([]rune(iText)) - len([]rune(text))
+	iRunes, runes := []rune(iText), []rune(text)
+
+	// look up the longest kanji phrase at the beginning of the text
+	dict := j.kanwa
+	converted, max_length, ok := j.kanwa.Lookup(runes, bText)
 
-	// load the kanwa table for the first character of the input text
-	table := j.kanwa.Load([]rune(text)[0])
-	if table == nil {
-		return "", 0, fmt.Errorf("no kanwa table found for the first character of the input text: %s", string([]rune(text[:1])))
+	// the user dictionary takes priority over the kanwa dictionary for matches of the same length
+	userKanwa, err := user.dict()
+	if err != nil {
+		return Match{}, err
 	}
 
-	// iterate through the kanwa table to find the longest matching key
-	iterator := table.Iter()
-	for k, vs, ok := iterator(); ok; k, vs, ok = iterator() {
-		key_length := len([]rune(k))
+	if userKanwa != nil {
+		if yomi, length, userOk := userKanwa.Lookup(runes, bText); userOk && length > 0 && length >= max_length {
+			dict, converted, max_length, ok = userKanwa, yomi, length, true
+		}
+	}
 
-		// if the key is longer than the input text, skip
-		switch {
-		case
-			len([]rune(text)) < key_length,
-			k != string([]rune(text)[:key_length]):
+	if !ok {
+		return Match{}, fmt.Errorf("no kanwa table found for the first character of the input text: %s", string(runes[0]))
+	}
 
-			continue
-		}
+	m := Match{
+		Yomi:      converted,
+		Length:    j.inputLength(iRunes, runes, max_length),
+		Ambiguous: max_length > 0 && dict.Ambiguous(runes, max_length, bText),
+	}
 
-		for _, v := range vs {
-			// retrieve the yomi and context of the key
-			if (len(v.Ctx) == 0 || v.Ctx.Contains(bText)) && max_length < key_length {
-				converted = v.Yomi
-				max_length = key_length
-			}
-		}
+	if user.cache != nil {
+		_ = user.cache.Add(iText+":"+bText, m)
 	}
 
+	return m, nil
+}
+
+// inputLength returns the length in characters of the input text iRunes of a phrase of the given length
+// at the beginning of its itaiji-converted form runes.
+func (j *JConv) inputLength(iRunes, runes []rune, length int) int {
+	// calculate the number of changed characters
+	num_changed_ch := len(iRunes) - len(runes)
+
 	// when converting string with kanji variant, the length of the converted string is not equal to the original string
-	// thus, calculate max_length to get the correct length of the converted string
-	for i := 0; i < num_changed_ch; i++ {
-		if max_length > len([]rune(iText)) {
+	// thus, calculate length to get the correct length of the converted string
+	for i := 0; i < num_changed_ch && length > 0; i++ {
+		if length > len(iRunes) {
 			break
 		}
 
 		switch {
 		case
 			// if the last character of the input text is a classified hiragana
-			[]rune(text)[max_length-1] != []rune(iText)[max_length-1],
+			runes[length-1] != iRunes[length-1],
 			// if the last character of the input text is an ideograph
-			max_length < num_changed_ch+len([]rune(text)) &&
-				max_length >= len([]rune(iText)) &&
-				j.IsVSCHR([]rune(iText)[max_length]):
+			length < num_changed_ch+len(runes) &&
+				length >= len(iRunes) &&
+				j.IsVSCHR(iRunes[length]):
 
-			max_length++
+			length++
 		}
 	}
 
-	defer j.cache.Add(iText+":"+bText, fmt.Sprintf("%s:%d", converted, max_length))
-	return converted, max_length, nil
+	return length
 }
 
 // IsCLetter returns true if the character is a classified hiragana.
@@ -103,30 +121,46 @@ func (j *JConv) IsVSCHR(ch rune) bool {
 	return 0x0E0100 <= ch && ch <= 0x0E01EF || 0xFE00 <= ch && ch <= 0xFE0F
 }
 
+// MaxKeyLen returns the length of the longest kanji phrase in characters.
+// The converter does not look further ahead in the input text than twice this length,
+// since each character of a phrase may be followed by a variation selector.
+func (j *JConv) MaxKeyLen() int {
+	if user, _ := j.user.Load().dict(); user != nil {
+		return max(j.kanwa.MaxKeyLen(), user.MaxKeyLen())
+	}
+
+	return j.kanwa.MaxKeyLen()
+}
+
 // IsRegion returns true if the character is an ideograph.
 func (j *JConv) IsRegion(ch rune) bool {
 	return 0x3400 <= ch && ch <= 0xE000 || j.itaiji.HasKey(ch)
 }
 
-func NewJConv() (*JConv, error) {
-	cache, err := lru.New[string, string](512)
+// NewJConv creates a new JConv instance.
+// The cacheSize sets the capacity of the conversion cache (0 disables caching).
+func NewJConv(cacheSize int) (*JConv, error) {
+	kanwa, err := sharedKanwa()
 	if err != nil {
 		return nil, err
 	}
 
-	kanwa, err := NewKanwa()
+	itaiji, err := sharedItaiji()
 	if err != nil {
 		return nil, err
 	}
 
-	itaiji, err := NewItaiji()
+	user, err := newUserDict(nil, cacheSize)
 	if err != nil {
 		return nil, err
 	}
 
-	return &JConv{
-		cache:  cache,
-		kanwa:  kanwa,
-		itaiji: itaiji,
-	}, nil
+	j := &JConv{
+		kanwa:     kanwa,
+		itaiji:    itaiji,
+		cacheSize: cacheSize,
+	}
+
+	j.user.Store(user)
+	return j, nil
 }
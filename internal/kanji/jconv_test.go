@@ -0,0 +1,87 @@
+package kanji
+
+import "testing"
+
+func TestNewJConv(t *testing.T) {
+	a, err := NewJConv(8)
+	if err != nil {
+		t.Errorf("NewJConv() error: %v", err)
+		return
+	}
+
+	b, err := NewJConv(8)
+	if err != nil {
+		t.Errorf("NewJConv() error: %v", err)
+		return
+	}
+
+	if a.kanwa != b.kanwa || a.itaiji != b.itaiji {
+		t.Errorf("NewJConv() loaded the dictionaries twice")
+	}
+
+	if a.user.Load().cache == b.user.Load().cache {
+		t.Errorf("NewJConv() shares the cache")
+	}
+}
+
+func TestReadings(t *testing.T) {
+	j, err := NewJConv(0)
+	if err != nil {
+		t.Fatalf("NewJConv() error: %v", err)
+	}
+
+	if err := j.AddWord("確", "たしか"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+
+	got := j.Readings('確')
+	if len(got) < 2 || got[0] != "たしか" || got[1] != "かく" {
+		t.Errorf("Readings(%q) = %q, want the user reading first, then かく", '確', got)
+	}
+
+	if got := j.Readings('a'); got != nil {
+		t.Errorf("Readings(%q) = %q, want nil", 'a', got)
+	}
+}
+
+func TestAddWordLoop(t *testing.T) {
+	j, err := NewJConv(0)
+	if err != nil {
+		t.Fatalf("NewJConv() error: %v", err)
+	}
+
+	// the dictionary is built on the first lookup, thus adding many words one by one does not rebuild it each time
+	const n = 20000
+	for i := range n {
+		if err := j.AddWord(string(rune(0x4E00+i)), "よみ"); err != nil {
+			t.Fatalf("AddWord() error: %v", err)
+		}
+	}
+
+	if err := j.AddWord(string(rune(0x4E00)), "さいご"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+
+	if got := len(j.user.Load().words); got != n+1 {
+		t.Errorf("len(words) = %d, want %d", got, n+1)
+	}
+
+	for _, tt := range []struct {
+		text, want string
+	}{
+		{string(rune(0x4E00)), "さいご"},
+		{string(rune(0x4E00 + n - 1)), "よみ"},
+	} {
+		if got, err := j.Convert(tt.text, ""); err != nil || got.Yomi != tt.want {
+			t.Errorf("Convert(%q) = %q, %v, want %q", tt.text, got.Yomi, err, tt.want)
+		}
+	}
+
+	if !j.RemoveWord(string(rune(0x4E00))) {
+		t.Errorf("RemoveWord(%q) = false, want true", string(rune(0x4E00)))
+	}
+
+	if got, err := j.Convert(string(rune(0x4E00)), ""); err != nil || got.Yomi == "さいご" {
+		t.Errorf("Convert(%q) = %q, %v, want the reading of the kanwa dictionary", string(rune(0x4E00)), got.Yomi, err)
+	}
+}
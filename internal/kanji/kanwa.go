@@ -1,31 +1,95 @@
 package kanji
 
 import (
-	"sync"
+	"slices"
 
 	"github.com/sarumaj/go-kakasi/internal/codegen"
 	"github.com/sarumaj/go-kakasi/internal/properties"
 )
 
-// Kanwa is a type that represents a map of Kanwa characters.
+// Kanwa is a type that represents a dictionary of kanji characters and phrases.
+// The phrases are stored in a trie, thus the longest match lookup runs in time proportional to the length of the match.
 type Kanwa struct {
-	sync.Mutex
-	kanwa *codegen.KanwaMap
+	trie *trie
 }
 
-// Load returns the KanjiCtxMap for the given key.
-// The key is the first character of the kanji character or phrase.
-// The KanjiCtxMap contains the reading and the contexts in which the kanji character or phrase is used.
-func (k *Kanwa) Load(key rune) *codegen.KanjiCtxMap {
-	k.Lock()
-	defer k.Unlock()
+// Lookup returns the reading of the longest kanji phrase at the beginning of the text and its length in characters.
+// Only readings without context or with a context matching the bText and the text following the phrase are considered,
+// the first matching reading of a phrase is returned.
+// It returns false, if there is no phrase beginning with the first character of the text.
+func (k *Kanwa) Lookup(text []rune, bText string) (string, int, bool) {
+	if len(text) == 0 {
+		return "", 0, false
+	}
 
-	if k.kanwa.Has(key) {
-		v := k.kanwa.Get(key)
-		return &v
+	if _, ok := k.trie.child(0, text[0]); !ok {
+		return "", 0, false
 	}
 
-	return nil
+	var yomi string
+	var length int
+	k.trie.walk(text, func(l int, readings []reading) bool {
+		for _, v := range readings {
+			if v.applies(text, l, bText) {
+				yomi, length = v.yomi, l
+				break
+			}
+		}
+
+		return true
+	})
+
+	return yomi, length, true
+}
+
+// Candidates returns the readings of all kanji phrases at the beginning of the text, which apply to their contexts.
+// The readings are ordered by the lengths of their phrases, the longest first, and by their order in the dictionary.
+// Duplicate readings of a phrase are returned once.
+func (k *Kanwa) Candidates(text []rune, bText string, source Source) []Candidate {
+	var candidates []Candidate
+	k.trie.walk(text, func(l int, readings []reading) bool {
+		var found []Candidate
+		for _, v := range readings {
+			if v.applies(text, l, bText) && !slices.ContainsFunc(found, func(c Candidate) bool { return c.Yomi == v.yomi }) {
+				found = append(found, Candidate{Yomi: v.yomi, Length: l, Source: source, Context: len(v.rule) > 0})
+			}
+		}
+
+		candidates = append(found, candidates...)
+		return true
+	})
+
+	return candidates
+}
+
+// Ambiguous returns true if the kanji phrase of the given length at the beginning of the text
+// has several distinct readings, which apply to its context.
+func (k *Kanwa) Ambiguous(text []rune, length int, bText string) bool {
+	var first string
+	for _, v := range k.trie.find(text[:length]) {
+		switch {
+		case !v.applies(text, length, bText):
+			continue
+
+		case first == "":
+			first = v.yomi
+
+		case v.yomi != first:
+			return true
+
+		}
+	}
+
+	return false
+}
+
+// MaxKeyLen returns the length of the longest kanji phrase in characters.
+func (k *Kanwa) MaxKeyLen() int { return k.trie.depth }
+
+// applies returns true if the reading of the phrase of the given length at the beginning of the text
+// has no context or a context matching the bText and the text following the phrase.
+func (v reading) applies(text []rune, length int, bText string) bool {
+	return v.rule.Match(bText, func() string { return string(text[length:]) })
 }
 
 // NewKanwa returns a new Kanwa instance.
@@ -35,5 +99,24 @@ func NewKanwa() (*Kanwa, error) {
 		return nil, err
 	}
 
-	return &Kanwa{kanwa: k}, nil
+	t, err := newTrie(kanwaEntries(k))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Kanwa{trie: t}, nil
+}
+
+// kanwaEntries returns the kanji phrases and their readings of the kanwa map.
+func kanwaEntries(k *codegen.KanwaMap) []trieEntry {
+	var entries []trieEntry
+	iterator := k.Iter()
+	for _, table, ok := iterator(); ok; _, table, ok = iterator() {
+		tableIterator := table.Iter()
+		for key, pairs, ok := tableIterator(); ok; key, pairs, ok = tableIterator() {
+			entries = append(entries, trieEntry{key: key, pairs: pairs})
+		}
+	}
+
+	return entries
 }
@@ -0,0 +1,128 @@
+package kanji
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/sarumaj/go-kakasi/internal/codegen"
+	"github.com/sarumaj/go-kakasi/internal/properties"
+)
+
+// testConstitution is the preamble of the Constitution of Japan.
+const testConstitution = "日本国民は、正当に選挙された国会における代表者を通じて行動し、われらとわれらの子孫のために、" +
+	"諸国民との協和による成果と、わが国全土にわたつて自由のもたらす恵沢を確保し、" +
+	"政府の行為によつて再び戦争の惨禍が起ることのないやうにすることを決意し、ここに主権が国民に存することを宣言し、" +
+	"この憲法を確定する。そもそも国政は、国民の厳粛な信託によるものであつて、その権威は国民に由来し、" +
+	"その権力は国民の代表者がこれを行使し、その福利は国民がこれを享受する。これは人類普遍の原理であり、" +
+	"この憲法は、かかる原理に基くものである。われらは、これに反する一切の憲法、法令及び詔勅を排除する。"
+
+// linearLookup is the longest match lookup iterating all phrases beginning with the first character of the text.
+// It serves as the reference for the trie-based lookup.
+func linearLookup(m *codegen.KanwaMap, text []rune, bText string) (string, int, bool) {
+	if !m.Has(text[0]) {
+		return "", 0, false
+	}
+
+	var converted string
+	var max_length int
+
+	s := string(text)
+	iterator := m.Get(text[0]).Iter()
+	for k, vs, ok := iterator(); ok; k, vs, ok = iterator() {
+		if !strings.HasPrefix(s, k) {
+			continue
+		}
+
+		key_length := utf8.RuneCountInString(k)
+		for _, v := range vs {
+			if (len(v.Ctx) == 0 || v.Ctx.Contains(bText)) && max_length < key_length {
+				converted = v.Yomi
+				max_length = key_length
+			}
+		}
+	}
+
+	return converted, max_length, true
+}
+
+// lookupWindows returns the texts looked up while converting the text, i.e. the text at each of its characters.
+func lookupWindows(text string, size int) [][]rune {
+	runes := []rune(text)
+	windows := make([][]rune, len(runes))
+	for i := range runes {
+		windows[i] = runes[i:min(len(runes), i+size)]
+	}
+
+	return windows
+}
+
+func TestKanwaLookup(t *testing.T) {
+	m, err := properties.Configurations.JisyoKanwa()
+	if err != nil {
+		t.Errorf("JisyoKanwa() error: %v", err)
+		return
+	}
+
+	tr, err := newTrie(kanwaEntries(m))
+	if err != nil {
+		t.Errorf("newTrie() error: %v", err)
+		return
+	}
+
+	k := &Kanwa{trie: tr}
+
+	var texts [][]rune
+	for i, key := range m.Keys() {
+		if i%16 == 0 { // a sample of the first characters
+			for _, phrase := range m.Get(key).Keys() {
+				texts = append(texts, []rune(phrase+"の"))
+			}
+		}
+	}
+
+	texts = append(texts, lookupWindows(testConstitution, 2*k.MaxKeyLen()+1)...)
+	texts = append(texts, []rune("a"), []rune("々"))
+
+	for _, bText := range []string{"", "あ", "がう"} {
+		for _, text := range texts {
+			gotYomi, gotLength, gotOk := k.Lookup(text, bText)
+			wantYomi, wantLength, wantOk := linearLookup(m, text, bText)
+			if gotYomi != wantYomi || gotLength != wantLength || gotOk != wantOk {
+				t.Errorf("(*Kanwa).Lookup(%q, %q) = (%q, %d, %t), want (%q, %d, %t)",
+					string(text), bText, gotYomi, gotLength, gotOk, wantYomi, wantLength, wantOk)
+			}
+		}
+	}
+}
+
+func BenchmarkKanwaLookup(b *testing.B) {
+	m, err := properties.Configurations.JisyoKanwa()
+	if err != nil {
+		b.Fatalf("JisyoKanwa() error: %v", err)
+	}
+
+	tr, err := newTrie(kanwaEntries(m))
+	if err != nil {
+		b.Fatalf("newTrie() error: %v", err)
+	}
+
+	k := &Kanwa{trie: tr}
+	windows := lookupWindows(testConstitution, 2*k.MaxKeyLen()+1)
+
+	b.Run("trie", func(b *testing.B) {
+		for b.Loop() {
+			for _, w := range windows {
+				_, _, _ = k.Lookup(w, "")
+			}
+		}
+	})
+
+	b.Run("linear", func(b *testing.B) {
+		for b.Loop() {
+			for _, w := range windows {
+				_, _, _ = linearLookup(m, w, "")
+			}
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package kanji
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The costs of the lattice segmentation, the path with the lowest total cost is chosen.
+const (
+	latticePhraseCost    = 100  // cost of each phrase, which favors few long phrases
+	latticeUserCost      = -20  // cost added to the phrases of the user dictionary
+	latticeKanaCost      = 20   // cost of a kana character outside of a phrase
+	latticeOkuriganaCost = 50   // cost of a kana character following a kanji character outside of a phrase
+	latticeParticleCost  = 0    // cost of a particle following a kanji character
+	latticeUnknownCost   = 1000 // cost of a kanji character without a reading
+)
+
+// latticeSourceCosts are the costs per kanji character added to the phrases of the source dictionaries.
+// The unidict dictionaries distilled from UniDic contain many rare names and compounds (e.g. 四月一日 read わたぬき),
+// thus their long phrases lose against the phrases of the kakasidict, while their short phrases still win against single kanji.
+var latticeSourceCosts = map[string]int{
+	"unidict_noun": 30,
+	"unidict_adj":  30,
+}
+
+// latticeParticles are the particles, which connect a phrase to the following text.
+const latticeParticles = "はがをにのでともへやかねよ"
+
+// latticeNode is a position of the lattice and the best path reaching it.
+type latticeNode struct {
+	reached bool
+	cost    int
+	prev    int    // position of the preceding node of the best path
+	start   int    // start of the segment ending at the node, i.e. prev or the start of a run of kana
+	kana    bool   // the best path reaches the node with a kana character
+	yomi    string // reading of the phrase reaching the node
+	dict    *Kanwa // dictionary of the phrase reaching the node, nil for kana and unknown kanji
+}
+
+// ConvertLattice converts the input text to the yomi reading like Convert, but instead of the longest match,
+// it chooses the path with the lowest cost through the lattice of all phrases of the dictionaries,
+// which covers the kanji and hiragana characters at the beginning of the input text.
+// The costs depend on the length of the phrases, their source dictionary and the kana connecting them.
+// It returns the reading of the first phrase of the path, a zero length if the first kanji character has no reading.
+func (j *JConv) ConvertLattice(iText, bText string) (Match, error) {
+	user := j.user.Load()
+
+	// check if the conversion is already cached
+	key := iText + ":" + bText + ":lattice"
+	if user.cache != nil {
+		if cached, ok := user.cache.Get(key); ok {
+			return cached, nil
+		}
+	}
+
+	// convert itaiji characters to their original form
+	text := j.itaiji.Convert(iText)
+	if len(text) == 0 {
+		return Match{}, fmt.Errorf("input text is empty")
+	}
+
+	iRunes, runes := []rune(iText), []rune(text)
+
+	// the lattice covers the run of kanji and hiragana characters
+	n := 0
+	for n < len(runes) && (j.IsRegion(runes[n]) || isHiragana(runes[n])) {
+		n++
+	}
+
+	userKanwa, err := user.dict()
+	if err != nil {
+		return Match{}, err
+	}
+
+	dicts := []*Kanwa{j.kanwa}
+	if userKanwa != nil {
+		dicts = []*Kanwa{userKanwa, j.kanwa}
+	}
+
+	nodes := make([]latticeNode, n+1)
+	nodes[0].reached = true
+
+	// the positions are relaxed in order, thus on equal costs the path with the longer phrase is kept
+	relax := func(from, to, cost int, node latticeNode) {
+		cost += nodes[from].cost
+		if !nodes[to].reached || cost < nodes[to].cost {
+			node.reached, node.cost, node.prev = true, cost, from
+			nodes[to] = node
+		}
+	}
+
+	for i := range n {
+		if !nodes[i].reached {
+			continue
+		}
+
+		// the context of the phrases is the segment preceding them
+		preceding := bText
+		if i > 0 {
+			preceding = string(runes[nodes[i].start:i])
+		}
+
+		if isHiragana(runes[i]) {
+			cost := latticeKanaCost
+			if i > 0 && j.IsRegion(runes[i-1]) {
+				cost = latticeOkuriganaCost
+				if strings.ContainsRune(latticeParticles, runes[i]) {
+					cost = latticeParticleCost
+				}
+			}
+
+			start := i
+			if nodes[i].kana {
+				start = nodes[i].start
+			}
+
+			relax(i, i+1, cost, latticeNode{start: start, kana: true})
+			continue
+		}
+
+		// the user dictionary takes priority over the kanwa dictionary for phrases of the same length
+		found := make(map[int]bool)
+		for _, dict := range dicts {
+			dict.trie.walk(runes[i:n], func(l int, readings []reading) bool {
+				for _, v := range readings {
+					if found[l] || !v.applies(runes[i:], l, preceding) {
+						continue
+					}
+
+					cost := latticePhraseCost + latticeSourceCosts[v.source]*j.countRegion(runes[i:i+l])
+					if dict == userKanwa {
+						cost += latticeUserCost
+					}
+
+					relax(i, i+l, cost, latticeNode{start: i, yomi: v.yomi, dict: dict})
+					found[l] = true
+				}
+
+				return true
+			})
+		}
+
+		if len(found) == 0 {
+			relax(i, i+1, latticeUnknownCost, latticeNode{start: i})
+		}
+	}
+
+	// the first phrase of the best path ends at the first node following the beginning
+	end := n
+	for end > 0 && nodes[end].prev > 0 {
+		end = nodes[end].prev
+	}
+
+	var m Match
+	if first := nodes[end]; end > 0 && first.dict != nil {
+		m = Match{
+			Yomi:      first.yomi,
+			Length:    j.inputLength(iRunes, runes, end),
+			Ambiguous: first.dict.Ambiguous(runes, end, bText),
+		}
+	}
+
+	if user.cache != nil {
+		_ = user.cache.Add(key, m)
+	}
+
+	return m, nil
+}
+
+// isHiragana returns true if the character is a hiragana.
+func isHiragana(ch rune) bool { return 0x3041 <= ch && ch <= 0x309F }
+
+// countRegion returns the number of kanji characters of the text.
+func (j *JConv) countRegion(text []rune) (n int) {
+	for _, ch := range text {
+		if j.IsRegion(ch) {
+			n++
+		}
+	}
+
+	return
+}
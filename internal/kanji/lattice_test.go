@@ -0,0 +1,36 @@
+package kanji
+
+import "testing"
+
+func TestJConv_ConvertLattice(t *testing.T) {
+	j, err := NewJConv(8)
+	if err != nil {
+		t.Fatalf("NewJConv() error: %v", err)
+	}
+
+	if err := j.AddWord("学校", "がくえん"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		text, bText string
+		want        Match
+	}{
+		{"四月一日に", "", Match{Yomi: "しがつ", Length: 2}},
+		{"日本語学校で", "", Match{Yomi: "にほんご", Length: 3}},
+		{"学校で", "", Match{Yomi: "がくえん", Length: 2}},
+		{"大人しい", "", Match{Yomi: "おとなしい", Length: 4}},
+		{"經済成長率", "", Match{Yomi: "けいざい", Length: 2}},
+	} {
+		got, err := j.ConvertLattice(tt.text, tt.bText)
+		if err != nil {
+			t.Errorf("ConvertLattice(%q, %q) error: %v", tt.text, tt.bText, err)
+			continue
+		}
+
+		got.Ambiguous = false
+		if got != tt.want {
+			t.Errorf("ConvertLattice(%q, %q) = %+v, want %+v", tt.text, tt.bText, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package kanji
+
+import (
+	"errors"
+	"sync"
+)
+
+// The dictionaries are immutable, thus they are loaded once on first use and shared by all converters.
+var (
+	sharedKanwa  = sync.OnceValues(NewKanwa)
+	sharedItaiji = sync.OnceValues(NewItaiji)
+)
+
+// Preload loads the dictionaries shared by all converters.
+func Preload() error {
+	_, kanwaErr := sharedKanwa()
+	_, itaijiErr := sharedItaiji()
+	return errors.Join(kanwaErr, itaijiErr)
+}
@@ -0,0 +1,141 @@
+package kanji
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sarumaj/go-kakasi/internal/codegen"
+)
+
+// trie is a prefix tree of kanji phrases.
+// The nodes are stored in a single slice, the children of a node are stored contiguously and sorted by their labels,
+// thus the child of a node is found by a binary search.
+type trie struct {
+	nodes  []trieNode
+	values [][]reading
+	depth  int // length of the longest phrase in characters
+}
+
+// reading is a reading of a kanji phrase and the compiled rule of its contexts.
+type reading struct {
+	yomi   string
+	rule   codegen.Rule
+	source string // name of the source dictionary
+}
+
+// trieNode is a node of the trie.
+type trieNode struct {
+	label       rune
+	first, last int32 // range of the children in the nodes of the trie
+	value       int32 // index of the readings in the values of the trie plus one, 0 if the node is not a phrase
+}
+
+// trieEntry is a kanji phrase and its readings.
+type trieEntry struct {
+	key   string
+	pairs []codegen.KanjiCtxPair
+}
+
+// child returns the child of the node with the given label.
+func (t *trie) child(node int32, label rune) (int32, bool) {
+	n := t.nodes[node]
+	i, ok := slices.BinarySearchFunc(t.nodes[n.first:n.last], label, func(n trieNode, label rune) int {
+		return cmp.Compare(n.label, label)
+	})
+
+	return n.first + int32(i), ok
+}
+
+// walk calls yield for each phrase which is a prefix of the text in the order of their lengths,
+// until yield returns false.
+func (t *trie) walk(text []rune, yield func(length int, readings []reading) bool) {
+	var node int32
+	for i, ch := range text {
+		var ok bool
+		if node, ok = t.child(node, ch); !ok {
+			return
+		}
+
+		if v := t.nodes[node].value; v > 0 && !yield(i+1, t.values[v-1]) {
+			return
+		}
+	}
+}
+
+// find returns the readings of the phrase, nil if the phrase is not in the trie.
+func (t *trie) find(phrase []rune) []reading {
+	var node int32
+	for _, ch := range phrase {
+		var ok bool
+		if node, ok = t.child(node, ch); !ok {
+			return nil
+		}
+	}
+
+	if v := t.nodes[node].value; v > 0 {
+		return t.values[v-1]
+	}
+
+	return nil
+}
+
+// newTrie builds a trie from the entries.
+// The readings of duplicate phrases are concatenated.
+// It returns an error if the context of a reading is invalid.
+func newTrie(entries []trieEntry) (*trie, error) {
+	// the order of UTF-8 strings equals the order of their characters
+	slices.SortStableFunc(entries, func(a, b trieEntry) int { return strings.Compare(a.key, b.key) })
+
+	// the nodes are built in breadth-first order, so that the children of each node are contiguous
+	// each job is a node and the range of the entries sharing its prefix,
+	// the depth is the length of the prefix in characters and the offset its length in bytes
+	type job struct {
+		node                  int32
+		lo, hi, depth, offset int
+	}
+
+	t := &trie{nodes: []trieNode{{}}}
+	for queue := []job{{0, 0, len(entries), 0, 0}}; len(queue) > 0; queue = queue[1:] {
+		j := queue[0]
+
+		lo := j.lo
+		for ; lo < j.hi && len(entries[lo].key) == j.offset; lo++ {
+			if t.nodes[j.node].value == 0 {
+				t.values = append(t.values, nil)
+				t.nodes[j.node].value = int32(len(t.values))
+			}
+
+			for _, v := range entries[lo].pairs {
+				rule, err := v.Ctx.Compile()
+				if err != nil {
+					return nil, fmt.Errorf("%s %s: %w", v.Yomi, entries[lo].key, err)
+				}
+
+				t.values[len(t.values)-1] = append(t.values[len(t.values)-1], reading{yomi: v.Yomi, rule: rule, source: v.Source})
+			}
+
+			t.depth = max(t.depth, j.depth)
+		}
+
+		t.nodes[j.node].first = int32(len(t.nodes))
+		for i := lo; i < j.hi; {
+			label, size := utf8.DecodeRuneInString(entries[i].key[j.offset:])
+
+			k := i + 1
+			for k < j.hi && strings.HasPrefix(entries[k].key[j.offset:], string(label)) {
+				k++
+			}
+
+			queue = append(queue, job{int32(len(t.nodes)), i, k, j.depth + 1, j.offset + size})
+			t.nodes = append(t.nodes, trieNode{label: label})
+			i = k
+		}
+
+		t.nodes[j.node].last = int32(len(t.nodes))
+	}
+
+	return t, nil
+}
@@ -0,0 +1,195 @@
+package kanji
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/sarumaj/go-kakasi/internal/codegen"
+)
+
+// userDict is an immutable snapshot of the user dictionary.
+// Each update of the user dictionary replaces the snapshot, thus conversions are never blocked by updates.
+// The conversion cache belongs to the snapshot, so that no results of an outdated dictionary are cached.
+// The dictionary of the words is built on the first lookup, thus a series of updates builds it once.
+type userDict struct {
+	words []word                 // in the order of addition, including words replaced by later ones
+	dict  func() (*Kanwa, error) // returns nil if there are no words
+	cache *lru.Cache[string, Match]
+}
+
+// word is a word of the user dictionary.
+// The yomi may end with an okurigana tail letter, see (codegen.KanwaMap).Add.
+type word struct {
+	kanji, yomi string
+	ctx         []string
+}
+
+// tail returns the okurigana tail letter of the yomi, 0 if there is none.
+func (w word) tail() rune {
+	if r := []rune(w.yomi); len(r) > 0 && r[len(r)-1] <= 'z' {
+		return r[len(r)-1]
+	}
+
+	return 0
+}
+
+// key identifies the word, a word added to the user dictionary replaces the word with the same key.
+func (w word) key() string {
+	return strings.Join(append([]string{w.kanji, string(w.tail())}, w.ctx...), "\x00")
+}
+
+// newUserDict returns a snapshot of the user dictionary with the given words and an empty conversion cache.
+func newUserDict(words []word, cacheSize int) (*userDict, error) {
+	d := &userDict{words: words, dict: sync.OnceValues(func() (*Kanwa, error) { return buildUserDict(words) })}
+	if cacheSize > 0 {
+		var err error
+		d.cache, err = lru.New[string, Match](cacheSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// buildUserDict returns the dictionary of the words, the last of the words with the same key replaces the others.
+func buildUserDict(words []word) (*Kanwa, error) {
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(words))
+	var kept []word
+	for _, w := range slices.Backward(words) {
+		if key := w.key(); !seen[key] {
+			seen[key] = true
+			kept = append(kept, w)
+		}
+	}
+
+	// the first matching reading of a phrase is used,
+	// thus the readings with contexts precede the readings without and the recent words precede the older ones
+	m := codegen.NewKanwaMap()
+	for _, withCtx := range []bool{true, false} {
+		for _, w := range kept {
+			if (len(w.ctx) > 0) == withCtx {
+				m.Add(w.kanji, w.yomi, w.ctx...)
+			}
+		}
+	}
+
+	t, err := newTrie(kanwaEntries(m))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Kanwa{trie: t}, nil
+}
+
+// AddWord adds a word to the user dictionary.
+// The yomi may end with an okurigana tail letter like in the kanwa dictionary,
+// e.g. ("書", "かk") adds "書か", "書き", "書く", "書け" and "書こ".
+// The word replaces a word added before with the same kanji, tail letter and contexts.
+// The words of the user dictionary take priority over the kanwa dictionary for matches of the same length.
+func (j *JConv) AddWord(kanji, yomi string, ctx ...string) error {
+	w, err := j.newWord(kanji, yomi, ctx...)
+	if err != nil {
+		return err
+	}
+
+	return j.addWords(w)
+}
+
+// LoadWords adds the words of a dictionary read by the parser, e.g. codegen.ParseKanwa or codegen.ParseSKK.
+// The words are added like by AddWord, either all or none of them.
+func (j *JConv) LoadWords(r io.Reader, parse codegen.KanwaParser) error {
+	var words []word
+	err := parse(r, func(kanji, yomi string, ctx ...string) error {
+		w, err := j.newWord(kanji, yomi, ctx...)
+		words = append(words, w)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return j.addWords(words...)
+}
+
+// newWord validates a word and returns it.
+func (j *JConv) newWord(kanji, yomi string, ctx ...string) (word, error) {
+	w := word{kanji: j.itaiji.Convert(kanji), yomi: yomi, ctx: slices.Clone(ctx)}
+	switch r := []rune(w.kanji); {
+	case len(r) == 0:
+		return word{}, fmt.Errorf("invalid kanji: %q is empty", kanji)
+
+	case !j.IsRegion(r[0]):
+		return word{}, fmt.Errorf("invalid kanji: %q does not begin with a kanji character", kanji)
+
+	}
+
+	if len(yomi) == 0 {
+		return word{}, fmt.Errorf("invalid yomi: %q is empty", yomi)
+	}
+
+	if _, err := codegen.KanjiCtx(ctx).Compile(); err != nil {
+		return word{}, err
+	}
+
+	if tail := w.tail(); tail != 0 {
+		if _, ok := codegen.CLetters[tail]; !ok {
+			return word{}, fmt.Errorf("invalid yomi: %q ends with an unknown okurigana letter %q", yomi, tail)
+		}
+
+		if len([]rune(yomi)) == 1 {
+			return word{}, fmt.Errorf("invalid yomi: %q has no reading", yomi)
+		}
+	}
+
+	return w, nil
+}
+
+// addWords adds the words to the user dictionary with a single update.
+// The words replaced by the added ones are dropped when the dictionary is built, see buildUserDict,
+// thus adding a word takes constant time apart from the validation.
+func (j *JConv) addWords(added ...word) error {
+	return j.updateUserDict(func(words []word) []word { return append(words, added...) })
+}
+
+// RemoveWord removes all words with the given kanji from the user dictionary.
+// It returns true if any word was removed. Unlike AddWord, it copies the words of the user dictionary.
+func (j *JConv) RemoveWord(kanji string) bool {
+	kanji = j.itaiji.Convert(kanji)
+
+	var removed bool
+	_ = j.updateUserDict(func(words []word) []word {
+		n := len(words)
+		words = slices.DeleteFunc(slices.Clone(words), func(w word) bool { return w.kanji == kanji })
+		removed = len(words) < n
+		return words
+	})
+
+	return removed
+}
+
+// updateUserDict replaces the user dictionary with a snapshot of the words returned by update.
+// The update receives the current words, which it may append to, but must not modify,
+// since the snapshots share them; the updates are serialized, so the current words are the longest ones sharing their array.
+// Errors of the words are reported by the lookups, since the dictionary is built on the first one.
+func (j *JConv) updateUserDict(update func([]word) []word) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	d, err := newUserDict(update(j.user.Load().words), j.cacheSize)
+	if err != nil {
+		return err
+	}
+
+	j.user.Store(d)
+	return nil
+}
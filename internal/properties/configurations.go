@@ -1,37 +1,35 @@
 package properties
 
 import (
-	"github.com/goccy/go-json"
+	"encoding"
+	"encoding/json"
 
 	"github.com/sarumaj/go-kakasi/internal/codegen"
 )
 
 // Configurations is a set of configuration values.
-// It is used to get the path to the dictionary files.
+// It is used to get the path to the dictionary files, which are given without the file extension of the format.
 // It is also used to read files from the file system.
 var Configurations = configurations{}
 
 type configurations struct{}
 
-func (configurations) jisyoHalfkana() string        { return "data/halfkana3.json" }
-func (configurations) jisyoHepburn() string         { return "data/hepburndict3.json" }
-func (configurations) jisyoHepburnHira() (v string) { return "data/hepburnhira3.json" }
-func (configurations) jisyoItaiji() string          { return "data/itaijidict4.json" }
-func (configurations) jisyoKanwa() string           { return "data/kanwadict4.json" }
-func (configurations) jisyoKunrei() string          { return "data/kunreidict3.json" }
-func (configurations) jisyoKunreiHira() string      { return "data/kunreihira3.json" }
-func (configurations) jisyoPassport() string        { return "data/passportdict3.json" }
-func (configurations) jisyoPassportHira() string    { return "data/passporthira3.json" }
-
-func (configurations) decode(path string, v any) error {
-	f, err := dataFS.Open(path)
-	if err != nil {
-		return err
-	}
-
-	defer f.Close()
-
-	return json.NewDecoder(f).Decode(v)
+func (configurations) jisyoHalfkana() string        { return "data/halfkana3" }
+func (configurations) jisyoHepburn() string         { return "data/hepburndict3" }
+func (configurations) jisyoHepburnHira() (v string) { return "data/hepburnhira3" }
+func (configurations) jisyoItaiji() string          { return "data/itaijidict4" }
+func (configurations) jisyoKanwa() string           { return "data/kanwadict4" }
+func (configurations) jisyoKunrei() string          { return "data/kunreidict3" }
+func (configurations) jisyoKunreiHira() string      { return "data/kunreihira3" }
+func (configurations) jisyoNihon() string           { return "data/nihondict3" }
+func (configurations) jisyoNihonHira() string       { return "data/nihonhira3" }
+func (configurations) jisyoPassport() string        { return "data/passportdict3" }
+func (configurations) jisyoPassportHira() string    { return "data/passporthira3" }
+
+// dictionary is a dictionary which can be decoded from the binary and the JSON format.
+type dictionary interface {
+	encoding.BinaryUnmarshaler
+	json.Unmarshaler
 }
 
 func (c configurations) JisyoHalfkana() (*codegen.LookupMap, error) {
@@ -97,6 +95,24 @@ func (c configurations) JisyoKunreiHira() (*codegen.LookupMap, error) {
 	return &v, nil
 }
 
+func (c configurations) JisyoNihon() (*codegen.LookupMap, error) {
+	var v codegen.LookupMap
+	if err := c.decode(c.jisyoNihon(), &v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+func (c configurations) JisyoNihonHira() (*codegen.LookupMap, error) {
+	var v codegen.LookupMap
+	if err := c.decode(c.jisyoNihonHira(), &v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
 func (c configurations) JisyoPassport() (*codegen.LookupMap, error) {
 	var v codegen.LookupMap
 	if err := c.decode(c.jisyoPassport(), &v); err != nil {
@@ -1,6 +1,18 @@
+//go:build !kakasi_json
+
 package properties
 
 import "embed"
 
-//go:embed data/*
+//go:embed data/*.bin
 var dataFS embed.FS
+
+// decode decodes a dictionary from the binary format.
+func (configurations) decode(path string, v dictionary) error {
+	data, err := dataFS.ReadFile(path + ".bin")
+	if err != nil {
+		return err
+	}
+
+	return v.UnmarshalBinary(data)
+}
@@ -0,0 +1,25 @@
+//go:build kakasi_json
+
+package properties
+
+import (
+	"embed"
+
+	"github.com/goccy/go-json"
+)
+
+//go:embed data/*.json
+var dataFS embed.FS
+
+// decode decodes a dictionary from the JSON format.
+// The JSON format is slow to load and serves for debugging only, see the build tag kakasi_json.
+func (configurations) decode(path string, v dictionary) error {
+	f, err := dataFS.Open(path + ".json")
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	return json.NewDecoder(f).Decode(v)
+}
@@ -0,0 +1,199 @@
+package script
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Aligner splits a ruby into the rubies of its parts, the parts without a reading are written as text, e.g. the okurigana.
+// The rubies of the parts carry their base text and reading only, thus their romaji are empty,
+// see (IConvertedSlice).FuriganizeAligned.
+type Aligner interface {
+	Align(ruby Ruby) []Ruby
+}
+
+// OkuriganaAligner leaves the kana of the base text outside of the rubies, e.g. 助[たす]け and 取[と]り扱[あつか]い.
+// The kana of the base text are matched against the reading, if they match in several ways, it keeps the group ruby.
+type OkuriganaAligner struct{}
+
+// Align implements Aligner.
+func (OkuriganaAligner) Align(ruby Ruby) []Ruby { return alignOkurigana(ruby) }
+
+// MonoAligner aligns the okurigana like OkuriganaAligner and splits the readings of the runs of kanji per character
+// (mono-ruby), e.g. 確[かく]定[てい]す, using the readings of the single characters.
+// The readings may be voiced at the beginning of a character following another one (rendaku, e.g. 本 ぼん),
+// and end with a small tsu before another one (sokuon, e.g. 学 がっ), 々 repeats the preceding character.
+// If the readings do not split the run in exactly one way, e.g. for 今日 read きょう, it keeps the group ruby of the run.
+type MonoAligner struct {
+	// Readings returns the readings of a kanji character in hiragana, see (Kakasi).MonoAligner.
+	Readings func(ch rune) []string
+}
+
+// Align implements Aligner.
+func (a MonoAligner) Align(ruby Ruby) []Ruby {
+	var parts []Ruby
+	for _, part := range alignOkurigana(ruby) {
+		if len(part.Reading) == 0 {
+			parts = append(parts, part)
+			continue
+		}
+
+		parts = append(parts, a.split(part)...)
+	}
+
+	return parts
+}
+
+// split returns the rubies of the characters of a run of kanji, or the run itself unless its reading splits in exactly one way.
+func (a MonoAligner) split(ruby Ruby) []Ruby {
+	base, reading := []rune(ruby.Base), []rune(ruby.Reading)
+	if len(base) < 2 || a.Readings == nil {
+		return []Ruby{ruby}
+	}
+
+	var found [][]string // distinct splits of the reading
+	var split []string
+	var search func(i, pos int)
+	search = func(i, pos int) {
+		if len(found) > 1 {
+			return
+		}
+
+		if i == len(base) {
+			if pos == len(reading) && !slices.ContainsFunc(found, func(s []string) bool { return slices.Equal(s, split) }) {
+				found = append(found, slices.Clone(split))
+			}
+
+			return
+		}
+
+		ch := base[i]
+		if ch == '々' && i > 0 {
+			ch = base[i-1]
+		}
+
+		for _, v := range monoVariants(a.Readings(ch), i > 0, i < len(base)-1) {
+			if strings.HasPrefix(string(reading[pos:]), v) {
+				split = append(split, v)
+				search(i+1, pos+len([]rune(v)))
+				split = split[:len(split)-1]
+			}
+		}
+	}
+
+	search(0, 0)
+	if len(found) != 1 {
+		return []Ruby{ruby}
+	}
+
+	parts := make([]Ruby, len(base))
+	for i, v := range found[0] {
+		parts[i] = Ruby{Base: string(base[i]), Reading: v, Converted: IConverted{Orig: string(base[i]), Hira: v}}
+	}
+
+	return parts
+}
+
+// monoVariants returns the readings along with their voiced (rendaku) and geminated (sokuon) variants.
+func monoVariants(readings []string, voiced, geminated bool) []string {
+	var variants []string
+	add := func(v string) {
+		if len(v) > 0 && !slices.Contains(variants, v) {
+			variants = append(variants, v)
+		}
+	}
+
+	for _, v := range readings {
+		add(v)
+
+		runes := []rune(v)
+		if voiced {
+			for _, d := range dakuten[runes[0]] {
+				add(string(d) + string(runes[1:]))
+			}
+		}
+
+		if last := runes[len(runes)-1]; geminated && len(runes) > 1 && strings.ContainsRune("くきちつ", last) {
+			add(string(runes[:len(runes)-1]) + "っ")
+		}
+	}
+
+	return variants
+}
+
+// dakuten are the voiced and semi-voiced kana of the unvoiced kana.
+var dakuten = map[rune][]rune{
+	'か': {'が'}, 'き': {'ぎ'}, 'く': {'ぐ'}, 'け': {'げ'}, 'こ': {'ご'},
+	'さ': {'ざ'}, 'し': {'じ'}, 'す': {'ず'}, 'せ': {'ぜ'}, 'そ': {'ぞ'},
+	'た': {'だ'}, 'ち': {'ぢ'}, 'つ': {'づ'}, 'て': {'で'}, 'と': {'ど'},
+	'は': {'ば', 'ぱ'}, 'ひ': {'び', 'ぴ'}, 'ふ': {'ぶ', 'ぷ'}, 'へ': {'べ', 'ぺ'}, 'ほ': {'ぼ', 'ぽ'},
+}
+
+// alignOkurigana splits the ruby at the kana of the base text, which are matched against the reading.
+// It returns the ruby itself unless they match in exactly one way.
+func alignOkurigana(ruby Ruby) []Ruby {
+	// the runs of the base text, alternating between kanji and kana
+	var runs []string
+	var kana []bool
+	for _, ch := range ruby.Base {
+		isKana := unicode.Is(unicode.Hiragana, ch)
+		if len(runs) == 0 || kana[len(kana)-1] != isKana {
+			runs, kana = append(runs, ""), append(kana, isKana)
+		}
+
+		runs[len(runs)-1] += string(ch)
+	}
+
+	if !slices.Contains(kana, true) || !slices.Contains(kana, false) {
+		return []Ruby{ruby}
+	}
+
+	var found [][]string // readings of the runs of each match
+	readings := make([]string, len(runs))
+	var match func(i int, rest string)
+	match = func(i int, rest string) {
+		if len(found) > 1 {
+			return
+		}
+
+		if i == len(runs) {
+			if len(rest) == 0 {
+				found = append(found, slices.Clone(readings))
+			}
+
+			return
+		}
+
+		if kana[i] {
+			if strings.HasPrefix(rest, runs[i]) {
+				readings[i] = ""
+				match(i+1, rest[len(runs[i]):])
+			}
+
+			return
+		}
+
+		// a run of kanji reads at least one kana
+		runes := []rune(rest)
+		for l := 1; l <= len(runes); l++ {
+			readings[i] = string(runes[:l])
+			match(i+1, string(runes[l:]))
+		}
+	}
+
+	match(0, ruby.Reading)
+	if len(found) != 1 {
+		return []Ruby{ruby}
+	}
+
+	parts := make([]Ruby, len(runs))
+	for i, run := range runs {
+		parts[i] = Ruby{Base: run, Reading: found[0][i]}
+		if !kana[i] {
+			parts[i].Converted = IConverted{Orig: run, Hira: found[0][i]}
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,52 @@
+package script
+
+import "testing"
+
+func TestAlign(t *testing.T) {
+	readings := map[rune][]string{
+		'確': {"かく"}, '定': {"てい", "さだ"}, '学': {"がく"}, '校': {"こう"}, '人': {"ひと", "じん"},
+		'本': {"ほん"}, '日': {"ひ", "にち"}, '今': {"いま", "こん"}, '亜': {"あ"},
+	}
+	mono := MonoAligner{Readings: func(ch rune) []string { return readings[ch] }}
+
+	for _, tt := range []struct {
+		aligner    Aligner
+		base, hira string
+		want       string
+	}{
+		{OkuriganaAligner{}, "助け", "たすけ", "助[たす]け"},
+		{OkuriganaAligner{}, "再び", "ふたたび", "再[ふたた]び"},
+		{OkuriganaAligner{}, "取り扱い", "とりあつかい", "取[と]り扱[あつか]い"},
+		{OkuriganaAligner{}, "確定す", "かくていす", "確定[かくてい]す"},
+		{OkuriganaAligner{}, "亜あ亜", "ああああ", "亜あ亜[ああああ]"}, // ambiguous
+		{OkuriganaAligner{}, "漢字", "かんじ", "漢字[かんじ]"},
+		{mono, "確定す", "かくていす", "確[かく]定[てい]す"},
+		{mono, "学校", "がっこう", "学[がっ]校[こう]"},
+		{mono, "人々", "ひとびと", "人[ひと]々[びと]"},
+		{mono, "日本", "にっぽん", "日[にっ]本[ぽん]"},
+		{mono, "日本", "やまと", "日本[やまと]"}, // no split
+		{mono, "今日", "きょう", "今日[きょう]"}, // no split
+		{mono, "亜亜", "ああ", "亜[あ]亜[あ]"},
+	} {
+		converted := IConvertedSlice{{Orig: tt.base, Hira: tt.hira}}
+		if got := converted.FuriganizeAligned(FuriganaBrackets{}, tt.aligner); got != tt.want {
+			t.Errorf("IConvertedSlice.FuriganizeAligned(%T) of %q = %q, want %q", tt.aligner, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestAlignHTML(t *testing.T) {
+	converted := IConvertedSlice{{Orig: "助けて", Hira: "たすけて", Hepburn: "tasukete"}}
+
+	for _, tt := range []struct {
+		formatter FuriganaHTML
+		want      string
+	}{
+		{FuriganaHTML{}, "<ruby>助<rp>(</rp><rt>たす</rt><rp>)</rp></ruby>けて"},
+		{FuriganaHTML{Romaji: SystemHepburn}, "<ruby>助けて<rp>(</rp><rt>たすけて</rt><rp>)</rp><rtc>tasukete</rtc></ruby>"},
+	} {
+		if got := converted.FuriganizeAligned(tt.formatter, OkuriganaAligner{}); got != tt.want {
+			t.Errorf("IConvertedSlice.FuriganizeAligned(%+v) = %q, want %q", tt.formatter, got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,13 @@
 package script
 
 const (
-	MethodHepburn  method = "Hepburn"
-	MethodKunrei   method = "Kunrei"
-	MethodPassport method = "Passport"
+	MethodHepburn         method = "Hepburn"
+	MethodKunrei          method = "Kunrei"
+	MethodPassport        method = "Passport"
+	MethodModifiedHepburn method = "ModifiedHepburn" // Hepburn with macrons, e.g. Tōkyō
+	MethodALALC           method = "ALA-LC"          // romanization of the Library of Congress, e.g. Tōkyō
+	MethodISO3602         method = "ISO3602"         // ISO 3602 strict with circumflexes, e.g. Tôkyô
+	MethodNihon           method = "Nihon"           // Nihon-shiki, reversible, e.g. toukyou, tidimu
 )
 
 const (
@@ -13,11 +17,45 @@ const (
 	ModeK  mode = "K"
 )
 
+const (
+	SystemHira System = 1 << iota
+	SystemKana
+	SystemHepburn
+	SystemKunrei
+	SystemPassport
+	SystemModifiedHepburn
+	SystemALALC
+	SystemISO3602
+	SystemNihon
+
+	// SystemDefault are the systems computed unless selected otherwise.
+	SystemDefault = SystemHira | SystemKana | SystemHepburn | SystemKunrei | SystemPassport
+
+	SystemAll = SystemDefault | SystemModifiedHepburn | SystemALALC | SystemISO3602 | SystemNihon
+)
+
 type Conf struct {
 	Method method
 	Mode   mode
 }
 
+// IConvConf is a configuration of the IConv converter.
+// Systems selects the fields of IConverted to compute,
+// Styles sets the rendering of the syllabic n and the long marks of the romanized systems,
+// CacheSize sets the capacity of the result cache (0 disables caching).
+type IConvConf struct {
+	Systems   System
+	Styles    map[System]Style
+	CacheSize int
+}
+
+// System is a set of output systems of the IConv converter.
+// Each system corresponds to a field of IConverted.
+type System uint
+
+// Has returns true if all systems in s are enabled.
+func (v System) Has(s System) bool { return v&s == s }
+
 type method string
 
 type mode string
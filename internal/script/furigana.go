@@ -0,0 +1,173 @@
+package script
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/sarumaj/go-kakasi/internal/properties"
+)
+
+// FuriganaFormatter writes the segments of a converted text with furigana, see (IConvertedSlice).FuriganizeWith.
+type FuriganaFormatter interface {
+	// WriteText writes text without furigana, e.g. kana, symbols and the end marks following a ruby.
+	WriteText(w *strings.Builder, text string)
+
+	// WriteRuby writes the base text with its reading.
+	WriteRuby(w *strings.Builder, ruby Ruby)
+}
+
+// romajiFormatter is implemented by the formatters which annotate the rubies with the romaji of an output system.
+type romajiFormatter interface {
+	romaji() System
+}
+
+// Ruby is a base text annotated with its reading.
+type Ruby struct {
+	Base      string     // base text, e.g. 漢字
+	Reading   string     // reading in hiragana, e.g. かんじ
+	Converted IConverted // converted segment of the base text, e.g. for its romaji
+}
+
+// Romaji returns the field of the converted segment corresponding to the output system without the trailing punctuation,
+// i.e. the romaji of the base text.
+func (r Ruby) Romaji(system System) string {
+	return strings.TrimRightFunc(r.Converted.Field(system), func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSpace(r) })
+}
+
+// FuriganaBrackets writes the readings in brackets following the base text, e.g. 漢字[かんじ].
+type FuriganaBrackets struct{}
+
+// WriteText implements FuriganaFormatter.
+func (FuriganaBrackets) WriteText(w *strings.Builder, text string) { w.WriteString(text) }
+
+// WriteRuby implements FuriganaFormatter.
+func (FuriganaBrackets) WriteRuby(w *strings.Builder, ruby Ruby) {
+	w.WriteString(ruby.Base + "[" + ruby.Reading + "]")
+}
+
+// FuriganaHTML writes HTML ruby elements with fallback parentheses, e.g. <ruby>漢字<rp>(</rp><rt>かんじ</rt><rp>)</rp></ruby>.
+// The text is HTML escaped.
+type FuriganaHTML struct {
+	// Romaji is the output system of the romaji annotation of the rubies in an rtc element, e.g. SystemHepburn,
+	// which is omitted unless set.
+	Romaji System
+}
+
+// romaji implements romajiFormatter.
+func (f FuriganaHTML) romaji() System { return f.Romaji }
+
+// WriteText implements FuriganaFormatter.
+func (FuriganaHTML) WriteText(w *strings.Builder, text string) {
+	w.WriteString(html.EscapeString(text))
+}
+
+// WriteRuby implements FuriganaFormatter.
+func (f FuriganaHTML) WriteRuby(w *strings.Builder, ruby Ruby) {
+	w.WriteString("<ruby>" + html.EscapeString(ruby.Base))
+	w.WriteString("<rp>(</rp><rt>" + html.EscapeString(ruby.Reading) + "</rt><rp>)</rp>")
+	if romaji := ruby.Romaji(f.Romaji); len(romaji) > 0 {
+		w.WriteString("<rtc>" + html.EscapeString(romaji) + "</rtc>")
+	}
+
+	w.WriteString("</ruby>")
+}
+
+// FuriganaAozora writes the ruby notation of Aozora Bunko, e.g. ｜漢字《かんじ》.
+type FuriganaAozora struct{}
+
+// WriteText implements FuriganaFormatter.
+func (FuriganaAozora) WriteText(w *strings.Builder, text string) { w.WriteString(text) }
+
+// WriteRuby implements FuriganaFormatter.
+func (FuriganaAozora) WriteRuby(w *strings.Builder, ruby Ruby) {
+	w.WriteString("｜" + ruby.Base + "《" + ruby.Reading + "》")
+}
+
+// FuriganaLaTeX writes the ruby commands of the pxrubrica package, e.g. \ruby{漢字}{かんじ}.
+// The special characters of LaTeX are escaped.
+type FuriganaLaTeX struct{}
+
+// WriteText implements FuriganaFormatter.
+func (FuriganaLaTeX) WriteText(w *strings.Builder, text string) {
+	w.WriteString(latexEscaper.Replace(text))
+}
+
+// WriteRuby implements FuriganaFormatter.
+func (FuriganaLaTeX) WriteRuby(w *strings.Builder, ruby Ruby) {
+	w.WriteString(`\ruby{` + latexEscaper.Replace(ruby.Base) + "}{" + latexEscaper.Replace(ruby.Reading) + "}")
+}
+
+// latexEscaper escapes the special characters of LaTeX.
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "$", `\$`, "&", `\&`, "#", `\#`,
+	"%", `\%`, "_", `\_`, "^", `\textasciicircum{}`, "~", `\textasciitilde{}`,
+)
+
+// FuriganaAnki writes the furigana notation of Anki, e.g. 漢字[かんじ],
+// the rubies are preceded by a space, which delimits the base text, unless at the beginning of the output.
+type FuriganaAnki struct{}
+
+// WriteText implements FuriganaFormatter.
+func (FuriganaAnki) WriteText(w *strings.Builder, text string) { w.WriteString(text) }
+
+// WriteRuby implements FuriganaFormatter.
+func (FuriganaAnki) WriteRuby(w *strings.Builder, ruby Ruby) {
+	if w.Len() > 0 {
+		w.WriteString(" ")
+	}
+
+	w.WriteString(ruby.Base + "[" + ruby.Reading + "]")
+}
+
+// Furiganize returns a string with furigana in brackets, e.g. 漢字[かんじ], see FuriganizeWith for other formats.
+func (i IConvertedSlice) Furiganize() string { return i.FuriganizeWith(FuriganaBrackets{}) }
+
+// FuriganizeWith returns a string with furigana written by the formatter.
+// The segments read unlike they are written get a ruby, the end marks following them are written as text.
+// The readings are the Hira fields of the segments, which are computed only if the output systems include SystemHira.
+// A segment without a reading, e.g. an unknown kanji or a text converted without SystemHira, gets a ruby with an empty reading,
+// e.g. 唸[] for FuriganaBrackets.
+func (i IConvertedSlice) FuriganizeWith(f FuriganaFormatter) string {
+	return i.FuriganizeAligned(f, nil)
+}
+
+// FuriganizeAligned returns a string with furigana written by the formatter like FuriganizeWith,
+// with the rubies split into their parts by the aligner, e.g. OkuriganaAligner. A nil aligner keeps the group rubies.
+// The parts carry no romaji, thus the group rubies are kept as well if the formatter annotates the rubies with romaji,
+// e.g. FuriganaHTML with Romaji set.
+func (i IConvertedSlice) FuriganizeAligned(f FuriganaFormatter, a Aligner) string {
+	if r, ok := f.(romajiFormatter); ok && r.romaji() != 0 {
+		a = nil
+	}
+
+	var out strings.Builder
+	for _, v := range i {
+		base := strings.TrimRightFunc(v.Orig, properties.Ch.IsEndmark)
+		reading := strings.TrimRightFunc(v.Hira, properties.Ch.IsEndmark)
+		if v.Orig == v.Hira || v.Orig == v.Kana || len(base) == 0 {
+			f.WriteText(&out, v.Orig)
+			continue
+		}
+
+		ruby := Ruby{Base: base, Reading: reading, Converted: v}
+		if a == nil || len(reading) == 0 {
+			f.WriteRuby(&out, ruby)
+		} else {
+			for _, part := range a.Align(ruby) {
+				if len(part.Reading) == 0 {
+					f.WriteText(&out, part.Base)
+					continue
+				}
+
+				f.WriteRuby(&out, part)
+			}
+		}
+
+		if len(base) < len(v.Orig) {
+			f.WriteText(&out, v.Orig[len(base):])
+		}
+	}
+
+	return out.String()
+}
@@ -0,0 +1,57 @@
+package script
+
+import "testing"
+
+func TestFuriganizeWith(t *testing.T) {
+	converted := IConvertedSlice{
+		{Orig: "漢字", Hira: "かんじ", Kana: "カンジ", Hepburn: "kanji"},
+		{Orig: "と", Hira: "と", Kana: "ト", Hepburn: "to"},
+		{Orig: "<b>", Hira: "<b>", Kana: "<b>", Hepburn: "<b>"},
+		{Orig: "使う。", Hira: "つかう。", Kana: "ツカウ。", Hepburn: "tsukau."},
+		{Orig: "50%", Hira: "50%", Kana: "50%", Hepburn: "50%"},
+	}
+
+	for _, tt := range []struct {
+		name string
+		f    FuriganaFormatter
+		want string
+	}{
+		{"brackets", FuriganaBrackets{}, "漢字[かんじ]と<b>使う[つかう]。50%"},
+		{"html", FuriganaHTML{}, "<ruby>漢字<rp>(</rp><rt>かんじ</rt><rp>)</rp></ruby>と&lt;b&gt;" +
+			"<ruby>使う<rp>(</rp><rt>つかう</rt><rp>)</rp></ruby>。50%"},
+		{"html romaji", FuriganaHTML{Romaji: SystemHepburn}, "<ruby>漢字<rp>(</rp><rt>かんじ</rt><rp>)</rp><rtc>kanji</rtc></ruby>と&lt;b&gt;" +
+			"<ruby>使う<rp>(</rp><rt>つかう</rt><rp>)</rp><rtc>tsukau</rtc></ruby>。50%"},
+		{"aozora", FuriganaAozora{}, "｜漢字《かんじ》と<b>｜使う《つかう》。50%"},
+		{"latex", FuriganaLaTeX{}, `\ruby{漢字}{かんじ}と<b>\ruby{使う}{つかう}。50\%`},
+		{"anki", FuriganaAnki{}, "漢字[かんじ]と<b> 使う[つかう]。50%"},
+	} {
+		if got := converted.FuriganizeWith(tt.f); got != tt.want {
+			t.Errorf("%s: IConvertedSlice.FuriganizeWith() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if got, want := converted.Furiganize(), converted.FuriganizeWith(FuriganaBrackets{}); got != want {
+		t.Errorf("IConvertedSlice.Furiganize() = %q, want %q", got, want)
+	}
+}
+
+func TestFuriganizeWithoutReading(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		converted IConvertedSlice
+		want      string
+	}{
+		// the reading of an unknown kanji is empty, which is written as an empty ruby
+		{"unknown kanji", IConvertedSlice{{Orig: "唸。", Hira: "。", Hepburn: "."}, {Orig: "漢字", Hira: "かんじ", Hepburn: "kanji"}}, "唸[]。漢字[かんじ]"},
+		// without SystemHira, the segments carry no readings
+		{"without hira", IConvertedSlice{{Orig: "漢字", Hepburn: "kanji"}, {Orig: "と", Hepburn: "to"}}, "漢字[]と[]"},
+	} {
+		if got := tt.converted.Furiganize(); got != tt.want {
+			t.Errorf("%s: IConvertedSlice.Furiganize() = %q, want %q", tt.name, got, tt.want)
+		}
+
+		if got := tt.converted.FuriganizeAligned(FuriganaBrackets{}, OkuriganaAligner{}); got != tt.want {
+			t.Errorf("%s: IConvertedSlice.FuriganizeAligned() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -4,14 +4,15 @@ import (
 	"fmt"
 
 	"github.com/sarumaj/go-kakasi/internal/codegen"
-	"github.com/sarumaj/go-kakasi/internal/properties"
 )
 
 // Hira is a type that represents a Japanese text converter.
 // It is used to convert Hiragana and Extended Kana characters to Katakana or Romaji characters.
 type Hira struct {
-	kanaDict *codegen.LookupMap
-	mode     mode
+	kanaDict  *codegen.LookupMap
+	mode      mode
+	overrides map[string]string // conversions of the method replacing those of the dictionary
+	spelling  *spelling         // rules joining the syllables of the method, nil unless converting to romaji
 }
 
 // Convert converts Hiragana and Extended Kana characters to Katakana or Romaji characters.
@@ -25,6 +26,9 @@ func (h Hira) Convert(text string) (string, int, error) {
 
 	case Mode_a:
 		converted, max_length, err = h2.convert_a(text)
+		if err == nil {
+			converted, max_length = h.override(text, converted, max_length)
+		}
 
 	case ModeK:
 		converted, max_length, err = h.convertK(text)
@@ -41,6 +45,18 @@ func (h Hira) Convert(text string) (string, int, error) {
 	return converted, max_length, nil
 }
 
+// override returns the override of the method at the beginning of the text, if it is at least as long as the conversion.
+func (h Hira) override(text, converted string, length int) (string, int) {
+	runes := []rune(text)
+	for l := min(len(runes), overridesMaxLen); l > 0 && l >= length; l-- {
+		if v, ok := h.overrides[string(runes[:l])]; ok {
+			return v, l
+		}
+	}
+
+	return converted, length
+}
+
 // convertK converts Hiragana and Extended Kana characters to Katakana characters.
 func (h Hira) convertK(text string) (string, int, error) {
 	var converted string
@@ -82,8 +98,13 @@ func (Hira) IsRegion(ch rune) bool {
 }
 
 // NewHira creates a new Hira instance.
-func NewHira(conf Conf) (*Hira, error) {
+func NewHira(conf Conf) (*Hira, error) { return newHira(conf, Style{}) }
+
+// newHira creates a new Hira instance, which romanizes the syllabic n and the long marks in the style.
+func newHira(conf Conf, style Style) (*Hira, error) {
 	var kanaDict *codegen.LookupMap
+	var overrides map[string]string
+	var spelling *spelling
 
 	switch conf.Mode {
 
@@ -93,13 +114,24 @@ func NewHira(conf Conf) (*Hira, error) {
 		switch conf.Method {
 
 		case MethodHepburn:
-			kanaDict, err = properties.Configurations.JisyoHepburnHira()
+			kanaDict, err = jisyoHepburnHira()
 
 		case MethodKunrei:
-			kanaDict, err = properties.Configurations.JisyoKunreiHira()
+			kanaDict, err = jisyoKunreiHira()
 
 		case MethodPassport:
-			kanaDict, err = properties.Configurations.JisyoPassportHira()
+			kanaDict, err = jisyoPassportHira()
+
+		case MethodModifiedHepburn, MethodALALC:
+			kanaDict, err = jisyoHepburnHira()
+			overrides = hepburnOverrides
+
+		case MethodNihon:
+			kanaDict, err = jisyoNihonHira()
+
+		case MethodISO3602:
+			kanaDict, err = jisyoKunreiHira()
+			overrides = iso3602Overrides
 
 		default:
 			return nil, fmt.Errorf("invalid method: %s", conf.Method)
@@ -110,6 +142,11 @@ func NewHira(conf Conf) (*Hira, error) {
 			return nil, err
 		}
 
+		spelling, err = newSpelling(conf.Method, style)
+		if err != nil {
+			return nil, err
+		}
+
 	case ModeK:
 
 	default:
@@ -118,7 +155,9 @@ func NewHira(conf Conf) (*Hira, error) {
 	}
 
 	return &Hira{
-		kanaDict: kanaDict,
-		mode:     conf.Mode,
+		kanaDict:  kanaDict,
+		mode:      conf.Mode,
+		overrides: overrides,
+		spelling:  spelling,
 	}, nil
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	lru "github.com/hashicorp/golang-lru/v2"
 
@@ -14,9 +16,17 @@ import (
 // It is used to convert Japanese text to different formats.
 type IConv struct {
 	cache    *lru.Cache[string, *IConverted]
+	systems  System
 	h2ahConv *Hira
 	h2akConv *Hira
 	h2apConv *Hira
+	h2amConv *Hira // modified Hepburn
+	h2alConv *Hira // ALA-LC
+	h2aiConv *Hira // ISO 3602
+	h2anConv *Hira // Nihon-shiki
+
+	// h2ppConv returns the Hepburn converter of the passport names, which is built on the first use
+	h2ppConv func() (*Hira, error)
 	h2kConv  *Hira
 	k2hConv  *Kata
 	s2aConv  *Symbol
@@ -25,98 +35,123 @@ type IConv struct {
 func (c IConv) convert(text string, convert interface {
 	Convert(string) (string, int, error)
 }) (string, error) {
-	var converted string
+	var converted strings.Builder
 
-	for i := 0; i < len([]rune(text)); {
-		width := len([]rune(text))
-		if width > c.maxLen()+i {
-			width = c.maxLen() + i
-		}
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		width := min(len(runes), c.maxLen()+i)
 
-		result, length, err := convert.Convert(string([]rune(text)[i:width]))
+		result, length, err := convert.Convert(string(runes[i:width]))
 		if err != nil {
 			return "", err
 		}
 
 		switch _, isSymbol := convert.(*Symbol); {
 		case length > 0:
-			converted += result
+			converted.WriteString(result)
 			i += length
 
-		case isSymbol && properties.Ch.IsLongSymbol([]rune(text)[i]):
-			if len([]rune(converted)) > 0 {
-				converted += string([]rune(converted)[len([]rune(converted))-1])
+		case isSymbol && properties.Ch.IsLongSymbol(runes[i]):
+			if converted.Len() > 0 {
+				last, _ := utf8.DecodeLastRuneInString(converted.String())
+				converted.WriteRune(last)
 			} else {
-				converted += "-"
+				converted.WriteString("-")
 			}
 			i++
 
 		default:
-			converted += string([]rune(text)[i : i+1])
+			converted.WriteRune(runes[i])
 			i++
 
 		}
 	}
 
-	return converted, nil
+	return converted.String(), nil
 }
 
 // Convert converts the input text to different formats.
+// Only the fields of the systems the converter was configured with are computed.
 func (c IConv) Convert(text, hira string) (*IConverted, error) {
 	// check if the conversion is already cached
-	cached, ok := c.cache.Get(text + ":" + hira)
-	if ok {
-		return cached, nil
-	}
-
-	kana, err := c.convert(hira, c.h2kConv)
-	if err != nil {
-		return nil, err
+	key := text + ":" + hira
+	if c.cache != nil {
+		if cached, ok := c.cache.Get(key); ok {
+			return cached, nil
+		}
 	}
 
-	hira, err = c.convert(hira, c.k2hConv) // make sure hira is in hiragana (no katakana)
-	if err != nil {
-		return nil, err
-	}
+	result := IConverted{Orig: text}
 
-	result := IConverted{Orig: text, Hira: hira, Kana: kana}
-	result.Hepburn, err = c.convert(hira, c.h2ahConv)
-	if err != nil {
-		return nil, err
+	var err error
+	if c.systems.Has(SystemKana) {
+		result.Kana, err = c.convert(hira, c.h2kConv)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	result.Kunrei, err = c.convert(hira, c.h2akConv)
+	hira, err = c.convert(hira, c.k2hConv) // make sure hira is in hiragana (no katakana)
 	if err != nil {
 		return nil, err
 	}
 
-	result.Passport, err = c.convert(hira, c.h2apConv)
-	if err != nil {
-		return nil, err
+	if c.systems.Has(SystemHira) {
+		result.Hira = hira
 	}
 
-	result.Hepburn, err = c.convert(result.Hepburn, c.s2aConv)
-	if err != nil {
-		return nil, err
-	}
+	boundary := okuriganaOffset(text, hira)
+	for _, v := range []struct {
+		system System
+		conv   *Hira
+		field  *string
+	}{
+		{SystemHepburn, c.h2ahConv, &result.Hepburn},
+		{SystemKunrei, c.h2akConv, &result.Kunrei},
+		{SystemPassport, c.h2apConv, &result.Passport},
+		{SystemModifiedHepburn, c.h2amConv, &result.ModifiedHepburn},
+		{SystemALALC, c.h2alConv, &result.ALALC},
+		{SystemISO3602, c.h2aiConv, &result.ISO3602},
+		{SystemNihon, c.h2anConv, &result.Nihon},
+	} {
+		if !c.systems.Has(v.system) {
+			continue
+		}
 
-	result.Kunrei, err = c.convert(result.Kunrei, c.s2aConv)
-	if err != nil {
-		return nil, err
+		*v.field, err = c.romanize(hira, boundary, v.conv)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	result.Passport, err = c.convert(result.Passport, c.s2aConv)
-	if err != nil {
-		return nil, err
+	if c.cache != nil {
+		_ = c.cache.Add(key, &result)
 	}
 
-	_ = c.cache.Add(text+":"+hira, &result)
 	return &result, nil
 }
 
+// Hiragana converts the katakana of the text to hiragana, regardless of the systems the converter was configured with.
+func (c IConv) Hiragana(text string) (string, error) { return c.convert(text, c.k2hConv) }
+
 func (IConv) maxLen() int { return 32 }
 
+const (
+	ScriptKanji             Script = "Kanji"
+	ScriptHiragana          Script = "Hiragana"
+	ScriptKatakana          Script = "Katakana"
+	ScriptHalfwidthKatakana Script = "HalfwidthKatakana"
+	ScriptAlpha             Script = "Alpha"
+	ScriptSymbol            Script = "Symbol"
+	ScriptMixed             Script = "Mixed"
+)
+
+// Script is the class of the characters of a converted text.
+// Segments of kanji with okurigana (e.g. "使う") are of the class ScriptMixed.
+type Script string
+
 // IConverted is a type that represents a result of Japanese text conversion.
+// The offsets point into the input text, even if it was normalized before conversion.
 type IConverted struct {
 	Orig     string `json:"orig"`
 	Hira     string `json:"hira"`
@@ -124,6 +159,19 @@ type IConverted struct {
 	Hepburn  string `json:"hepburn"`
 	Kunrei   string `json:"kunrei"`
 	Passport string `json:"passport"`
+
+	ModifiedHepburn string `json:"modified_hepburn"` // not computed unless selected, see SystemDefault
+	ALALC           string `json:"ala_lc"`           // not computed unless selected, see SystemDefault
+	ISO3602         string `json:"iso3602"`          // not computed unless selected, see SystemDefault
+	Nihon           string `json:"nihon"`            // not computed unless selected, see SystemDefault
+
+	Script     Script `json:"script"`     // class of the characters of the segment
+	Dictionary bool   `json:"dictionary"` // reading looked up in the kanwa dictionary, otherwise the kana are passed through
+	Ambiguous  bool   `json:"ambiguous"`  // the dictionary has other readings of the phrase, see (Kakasi).ConvertCandidates
+	Start      int    `json:"start"`      // offset of the segment in the input text in characters
+	End        int    `json:"end"`        // end offset of the segment in the input text in characters
+	ByteStart  int    `json:"byte_start"` // offset of the segment in the input text in bytes
+	ByteEnd    int    `json:"byte_end"`   // end offset of the segment in the input text in bytes
 }
 
 // String returns a string representation of the IConverted.
@@ -131,7 +179,12 @@ func (i IConverted) String() string {
 	var out []string
 	v := reflect.Indirect(reflect.ValueOf(&i))
 	for i := 0; i < v.NumField(); i++ {
-		out = append(out, fmt.Sprintf("%s: %q", v.Type().Field(i).Name, v.Field(i).String()))
+		format := "%s: %v"
+		if v.Field(i).Kind() == reflect.String {
+			format = "%s: %q"
+		}
+
+		out = append(out, fmt.Sprintf(format, v.Type().Field(i).Name, v.Field(i).Interface()))
 	}
 
 	return fmt.Sprintf("{%s}", strings.Join(out, ", "))
@@ -140,26 +193,8 @@ func (i IConverted) String() string {
 // IConvertedSlice is a slice of IConverted.
 type IConvertedSlice []IConverted
 
-// Furiganize returns a string with furigana.
-func (i IConvertedSlice) Furiganize() string {
-	var out string
-	for _, v := range i {
-		out += v.Orig
-		if v.Orig != v.Hira && v.Orig != v.Kana {
-			out = strings.TrimRightFunc(out, properties.Ch.IsEndmark)
-			out += "[" + strings.TrimRightFunc(v.Hira, properties.Ch.IsEndmark) + "]"
-			for _, r := range v.Hira {
-				if properties.Ch.IsEndmark(r) {
-					out += string(r)
-				}
-			}
-		}
-	}
-
-	return out
-}
-
 // Romanize returns a string with romaji.
+// The Hepburn fields are joined with a single space, see Render for the other systems and options.
 func (i IConvertedSlice) Romanize() string {
 	var out []string
 	for _, v := range i {
@@ -179,33 +214,47 @@ func (i IConvertedSlice) String() string {
 	return fmt.Sprintf("[%s]", strings.Join(out, ", "))
 }
 
-func NewIConv() (*IConv, error) {
-	c := IConv{}
+// NewIConv creates a new IConv instance.
+// Converters of systems not selected by the configuration are not created.
+func NewIConv(conf IConvConf) (*IConv, error) {
+	c := IConv{systems: conf.Systems}
 	var err error
 
-	c.cache, err = lru.New[string, *IConverted](256)
-	if err != nil {
-		return nil, err
-	}
-
-	c.h2ahConv, err = NewHira(Conf{Method: MethodHepburn, Mode: Mode_a})
-	if err != nil {
-		return nil, err
+	if conf.CacheSize > 0 {
+		c.cache, err = lru.New[string, *IConverted](conf.CacheSize)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	c.h2akConv, err = NewHira(Conf{Method: MethodKunrei, Mode: Mode_a})
-	if err != nil {
-		return nil, err
-	}
+	for _, v := range []struct {
+		system System
+		method method
+		conv   **Hira
+	}{
+		{SystemHepburn, MethodHepburn, &c.h2ahConv},
+		{SystemKunrei, MethodKunrei, &c.h2akConv},
+		{SystemPassport, MethodPassport, &c.h2apConv},
+		{SystemModifiedHepburn, MethodModifiedHepburn, &c.h2amConv},
+		{SystemALALC, MethodALALC, &c.h2alConv},
+		{SystemISO3602, MethodISO3602, &c.h2aiConv},
+		{SystemNihon, MethodNihon, &c.h2anConv},
+	} {
+		if !c.systems.Has(v.system) {
+			continue
+		}
 
-	c.h2apConv, err = NewHira(Conf{Method: MethodPassport, Mode: Mode_a})
-	if err != nil {
-		return nil, err
+		*v.conv, err = newHira(Conf{Method: v.method, Mode: Mode_a}, conf.Styles[v.system])
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	c.h2kConv, err = NewHira(Conf{Mode: ModeK})
-	if err != nil {
-		return nil, err
+	if c.systems.Has(SystemKana) {
+		c.h2kConv, err = NewHira(Conf{Mode: ModeK})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	c.k2hConv, err = NewKata(Conf{Mode: ModeH})
@@ -213,6 +262,7 @@ func NewIConv() (*IConv, error) {
 		return nil, err
 	}
 
+	c.h2ppConv = sync.OnceValues(func() (*Hira, error) { return NewHira(Conf{Method: MethodHepburn, Mode: Mode_a}) })
 	c.s2aConv = NewSymbol(Mode_a)
 
 	return &c, nil
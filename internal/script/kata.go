@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/sarumaj/go-kakasi/internal/codegen"
-	"github.com/sarumaj/go-kakasi/internal/properties"
 )
 
 // Kata is a type that represents a Japanese text converter.
@@ -152,7 +151,7 @@ func (k Kata) IsRegion(ch rune) bool {
 }
 
 func NewKata(conf Conf) (*Kata, error) {
-	halfKanaDict, err := properties.Configurations.JisyoHalfkana()
+	halfKanaDict, err := jisyoHalfkana()
 	if err != nil {
 		return nil, err
 	}
@@ -167,13 +166,16 @@ func NewKata(conf Conf) (*Kata, error) {
 		switch conf.Method {
 
 		case MethodPassport:
-			kanaDict, err = properties.Configurations.JisyoPassport()
+			kanaDict, err = jisyoPassport()
 
 		case MethodKunrei:
-			kanaDict, err = properties.Configurations.JisyoKunrei()
+			kanaDict, err = jisyoKunrei()
 
 		case MethodHepburn:
-			kanaDict, err = properties.Configurations.JisyoHepburn()
+			kanaDict, err = jisyoHepburn()
+
+		case MethodNihon:
+			kanaDict, err = jisyoNihon()
 
 		default:
 			return nil, fmt.Errorf("invalid method: %v", conf.Method)
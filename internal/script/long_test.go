@@ -0,0 +1,70 @@
+package script
+
+import "testing"
+
+func TestIConv_ConvertLongVowels(t *testing.T) {
+	c, err := NewIConv(IConvConf{Systems: SystemModifiedHepburn | SystemALALC | SystemISO3602})
+	if err != nil {
+		t.Errorf("NewIConv() error: %v", err)
+		return
+	}
+
+	type want struct {
+		modifiedHepburn, alaLC, iso3602 string
+	}
+
+	for _, tt := range []struct {
+		text, hira string
+		want       want
+	}{
+		{"東京", "とうきょう", want{"tōkyō", "tōkyō", "tôkyô"}},
+		{"大きい", "おおきい", want{"ōkii", "ōkii", "ôkii"}},
+		{"お母さん", "おかあさん", want{"okāsan", "okāsan", "okâsan"}},
+		{"お姉さん", "おねえさん", want{"onēsan", "onēsan", "onêsan"}},
+		{"空気", "くうき", want{"kūki", "kūki", "kûki"}},
+		{"英語", "えいご", want{"eigo", "eigo", "eigo"}},
+		{"思う", "おもう", want{"omou", "omou", "omou"}},
+		{"思う。", "おもう。", want{"omou.", "omou.", "omou."}},
+		{"おもう", "おもう", want{"omou", "omou", "omou"}},
+		{"よくおもう。", "よくおもう。", want{"yokuomou.", "yokuomou.", "yokuomou."}},
+		{"くるう", "くるう", want{"kuruu", "kuruu", "kuruu"}},
+		{"どう", "どう", want{"dō", "dō", "dô"}},
+		{"がっこう", "がっこう", want{"gakkō", "gakkō", "gakkô"}},
+		{"ありがとう", "ありがとう", want{"arigatō", "arigatō", "arigatô"}},
+		{"コーヒー", "こーひー", want{"kōhī", "kōhii", "kôhî"}},
+		{"ちぢむ", "ちぢむ", want{"chijimu", "chijimu", "tidimu"}},
+		{"を", "を", want{"o", "o", "wo"}},
+		{"ー", "ー", want{"-", "-", "-"}},
+	} {
+		got, err := c.Convert(tt.text, tt.hira)
+		if err != nil {
+			t.Errorf("IConv.Convert(%q, %q) error: %v", tt.text, tt.hira, err)
+			continue
+		}
+
+		if w := (want{got.ModifiedHepburn, got.ALALC, got.ISO3602}); w != tt.want {
+			t.Errorf("IConv.Convert(%q, %q) = %+v, want %+v", tt.text, tt.hira, w, tt.want)
+		}
+	}
+}
+
+func Test_okuriganaOffset(t *testing.T) {
+	for _, tt := range []struct {
+		text, hira string
+		want       int
+	}{
+		{"思う", "おもう", 2},
+		{"思う。", "おもう。", 2},
+		{"東京", "とうきょう", -1},
+		{"とうきょう", "とうきょう", -1},
+		{"おもう", "おもう", 2},
+		{"よくおもう。", "よくおもう。", 4},
+		{"ふうとう", "ふうとう", -1},
+		{"ありがとう", "ありがとう", -1},
+		{"コーヒー", "こーひー", -1},
+	} {
+		if got := okuriganaOffset(tt.text, tt.hira); got != tt.want {
+			t.Errorf("okuriganaOffset(%q, %q) = %d, want %d", tt.text, tt.hira, got, tt.want)
+		}
+	}
+}
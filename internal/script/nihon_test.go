@@ -0,0 +1,141 @@
+package script
+
+import (
+	"strings"
+	"testing"
+)
+
+// fromNihon converts the Nihon-shiki romanization back to hiragana by the longest match of the inverted table,
+// the circumflexes are long marks following the vowel.
+func fromNihon(t *testing.T, inverse map[string]string, romaji string) string {
+	t.Helper()
+
+	runes := []rune(strings.NewReplacer("â", "aー", "î", "iー", "û", "uー", "ê", "eー", "ô", "oー").Replace(romaji))
+
+	var out strings.Builder
+	for i := 0; i < len(runes); {
+		if runes[i] == 'ー' {
+			out.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		var length int
+		for l := 1; l <= min(len(runes)-i, 4); l++ {
+			if _, ok := inverse[string(runes[i:i+l])]; ok {
+				length = l
+			}
+		}
+
+		if length == 0 {
+			t.Fatalf("fromNihon(%q): no kana at %d", romaji, i)
+		}
+
+		out.WriteString(inverse[string(runes[i:i+length])])
+		i += length
+	}
+
+	return out.String()
+}
+
+func TestNihonRoundTrip(t *testing.T) {
+	table, err := jisyoNihonHira()
+	if err != nil {
+		t.Fatalf("jisyoNihonHira() error: %v", err)
+	}
+
+	var kana []string
+	inverse := make(map[string]string)
+	for _, k := range table.Keys() {
+		if k == "_max_key_len_" {
+			continue
+		}
+
+		v := table.Get(k)
+		if prev, ok := inverse[v]; ok {
+			t.Errorf("jisyoNihonHira() maps %q and %q to %q", prev, k, v)
+		}
+
+		inverse[v] = k
+		kana = append(kana, k)
+	}
+
+	// the syllabic n followed by a vowel or y is separated by the converter
+	inverse["n'"] = "ん"
+
+	c, err := NewIConv(IConvConf{Systems: SystemNihon})
+	if err != nil {
+		t.Fatalf("NewIConv() error: %v", err)
+	}
+
+	texts := []string{"ちぢむ", "つづく", "ゐゑを", "くゎし", "こーひー", "きって", "あんない", "きんえん", "ほんや", "いっぬ"}
+	for _, a := range kana {
+		for _, b := range kana {
+			texts = append(texts, a+b)
+		}
+	}
+
+	for _, text := range texts {
+		got, err := c.Convert(text, text)
+		if err != nil {
+			t.Errorf("IConv.Convert(%q) error: %v", text, err)
+			continue
+		}
+
+		if back := fromNihon(t, inverse, got.Nihon); back != text {
+			t.Errorf("IConv.Convert(%q) = %q, which converts back to %q", text, got.Nihon, back)
+		}
+	}
+}
+
+func TestNihonConversion(t *testing.T) {
+	c, err := NewIConv(IConvConf{Systems: SystemNihon})
+	if err != nil {
+		t.Fatalf("NewIConv() error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		text, hira, want string
+	}{
+		{"東京", "とうきょう", "toukyou"},
+		{"縮む", "ちぢむ", "tidimu"},
+		{"続く", "つづく", "tuduku"},
+		{"火事", "くゎじ", "kwazi"},
+		{"を", "を", "wo"},
+		{"シャツ", "しゃつ", "syatu"},
+		{"コーヒー", "こーひー", "kôhî"},
+	} {
+		got, err := c.Convert(tt.text, tt.hira)
+		if err != nil {
+			t.Errorf("IConv.Convert(%q, %q) error: %v", tt.text, tt.hira, err)
+			continue
+		}
+
+		if got.Nihon != tt.want {
+			t.Errorf("IConv.Convert(%q, %q) = %q, want %q", tt.text, tt.hira, got.Nihon, tt.want)
+		}
+	}
+}
+
+func TestKataNihon(t *testing.T) {
+	k, err := NewKata(Conf{Method: MethodNihon, Mode: Mode_a})
+	if err != nil {
+		t.Fatalf("NewKata() error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		args, want string
+		length     int
+	}{
+		{"ヂ", "di", 1},
+		{"ヅ", "du", 1},
+		{"ヲ", "wo", 1},
+		{"クヮ", "kwa", 2},
+		{"ッテ", "tte", 2},
+	} {
+		got, length, err := k.Convert(tt.args)
+		if err != nil || got != tt.want || length != tt.length {
+			t.Errorf("Kata.Convert(%q) = (%q, %d, %v), want (%q, %d, nil)", tt.args, got, length, err, tt.want, tt.length)
+		}
+	}
+}
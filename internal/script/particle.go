@@ -0,0 +1,61 @@
+package script
+
+import "slices"
+
+// particleReadings are the romanizations of the particles, which are read unlike they are written.
+var particleReadings = map[rune]string{'は': "wa", 'へ': "e", 'を': "o"}
+
+// particleSystems are the systems which romanize the particles as they are read.
+// ISO 3602 strict and Nihon-shiki transliterate the kana, thus they romanize the particles as they are written.
+const particleSystems = SystemHepburn | SystemKunrei | SystemPassport | SystemModifiedHepburn | SystemALALC
+
+// ConvertParticles converts the kana text like Convert, but romanizes the characters at the offsets as particles,
+// e.g. は as wa instead of ha. The text is its own reading, i.e. it must not contain kanji.
+// The parts of the text between the particles are converted on their own.
+func (c IConv) ConvertParticles(text string, particles []int) (*IConverted, error) {
+	runes := []rune(text)
+	result := IConverted{Orig: text}
+
+	start := 0
+	for _, p := range append(slices.Clone(particles), len(runes)) {
+		if start < p {
+			part, err := c.Convert(string(runes[start:p]), string(runes[start:p]))
+			if err != nil {
+				return nil, err
+			}
+
+			result.append(*part)
+		}
+
+		if p < len(runes) {
+			part, err := c.Convert(string(runes[p]), string(runes[p]))
+			if err != nil {
+				return nil, err
+			}
+
+			particle := *part
+			if reading, ok := particleReadings[runes[p]]; ok {
+				for s := System(1); s <= particleSystems; s <<= 1 {
+					if particleSystems.Has(s) && c.systems.Has(s) {
+						*particle.field(s) = reading
+					}
+				}
+			}
+
+			result.append(particle)
+		}
+
+		start = p + 1
+	}
+
+	return &result, nil
+}
+
+// append appends the fields of the output systems of the converted text o.
+func (i *IConverted) append(o IConverted) {
+	for s := System(1); s <= SystemAll; s <<= 1 {
+		if f := i.field(s); f != nil {
+			*f += o.Field(s)
+		}
+	}
+}
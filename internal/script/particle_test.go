@@ -0,0 +1,33 @@
+package script
+
+import "testing"
+
+func TestConvertParticles(t *testing.T) {
+	c, err := NewIConv(IConvConf{Systems: SystemAll})
+	if err != nil {
+		t.Errorf("NewIConv() error: %v", err)
+		return
+	}
+
+	got, err := c.ConvertParticles("にはおおさかへ", []int{1, 6})
+	if err != nil {
+		t.Errorf("IConv.ConvertParticles() error: %v", err)
+		return
+	}
+
+	for system, want := range map[System]string{
+		SystemHira:            "にはおおさかへ",
+		SystemKana:            "ニハオオサカヘ",
+		SystemHepburn:         "niwaoosakae",
+		SystemKunrei:          "niwaoosakae",
+		SystemPassport:        "niwaosakae",
+		SystemModifiedHepburn: "niwaōsakae",
+		SystemALALC:           "niwaōsakae",
+		SystemISO3602:         "nihaôsakahe",
+		SystemNihon:           "nihaoosakahe",
+	} {
+		if got := got.Field(system); got != want {
+			t.Errorf("IConv.ConvertParticles(%q, %v).Field(%v) = %q, want %q", "にはおおさかへ", []int{1, 6}, system, got, want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package script
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/sarumaj/go-kakasi/internal/properties"
+)
+
+const (
+	PassportNotationHepburn PassportNotation = iota // long vowels omitted, e.g. 大野 ONO and 加藤 KATO
+	PassportNotationOH                              // OH for おお and おう, e.g. OHNO and KATOH
+	PassportNotationKana                            // OO and OU as written in kana, e.g. OONO and KATOU
+)
+
+// PassportNotation is the notation of the long vowels おお and おう of a passport name, e.g. PassportNotationOH.
+// The Hepburn notation is the rule, the others are allowed upon request of the applicant.
+type PassportNotation int
+
+// PassportName romanizes the hiragana of a name in upper case by the rules of the Japanese passport:
+//   - the syllables are written in Hepburn, e.g. し SHI, ち CHI, つ TSU and を O;
+//   - ん is written M before B, M and P, e.g. 難波 NAMBA, and N otherwise without an apostrophe;
+//   - っ doubles the following consonant, or is written T before CH, e.g. 発地 HATCHI;
+//   - the long vowels おお, おう and うう are not written, e.g. 大野 ONO and 優子 YUKO, unless in the notation,
+//     while the other vowels are kept, e.g. 新潟 NIIGATA, and so is the う or お followed by a vowel, e.g. 井上 INOUE;
+//   - the long marks are not written.
+func (c IConv) PassportName(hira string, notation PassportNotation) (string, error) {
+	units, err := c.passportUnits(hira)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	for _, alternatives := range units {
+		out.WriteString(alternatives[min(int(notation), len(alternatives)-1)])
+	}
+
+	return out.String(), nil
+}
+
+// PassportPattern returns the pattern of the romanized names compliant with the hiragana of the name in any notation,
+// each long vowel may be written in another one. The pattern matches the names in upper case with single spaces.
+func (c IConv) PassportPattern(hira string) (*regexp.Regexp, error) {
+	units, err := c.passportUnits(hira)
+	if err != nil {
+		return nil, err
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for _, alternatives := range units {
+		quoted := make([]string, len(alternatives))
+		for i, v := range alternatives {
+			quoted[i] = regexp.QuoteMeta(v)
+		}
+
+		pattern.WriteString("(?:" + strings.Join(quoted, "|") + ")")
+	}
+
+	pattern.WriteString("$")
+	return regexp.Compile(pattern.String())
+}
+
+// passportUnits returns the units of the romanized name in upper case,
+// each unit is a syllable or a long vowel and holds its alternatives indexed by the notation.
+// The spaces are collapsed and trimmed.
+func (c IConv) passportUnits(hira string) ([][]string, error) {
+	h2ppConv, err := c.h2ppConv()
+	if err != nil {
+		return nil, err
+	}
+
+	hira, err = c.convert(hira, c.k2hConv)
+	if err != nil {
+		return nil, err
+	}
+
+	var units [][]string
+	last := func() string {
+		if len(units) == 0 {
+			return ""
+		}
+
+		return units[len(units)-1][0]
+	}
+
+	runes := []rune(strings.Join(strings.Fields(hira), " "))
+	for i := 0; i < len(runes); {
+		ch := runes[i]
+
+		switch {
+		case ch == ' ':
+			units = append(units, []string{" "})
+			i++
+			continue
+
+		case properties.Ch.IsLongSymbol(ch):
+			units = append(units, []string{""})
+			i++
+			continue
+
+		}
+
+		// the long vowels are joined with the preceding syllable unless followed by a vowel
+		if vowel := last(); len(vowel) > 0 && (i+1 == len(runes) || !strings.ContainsRune("あいうえお", runes[i+1])) {
+			switch v := vowel[len(vowel)-1]; {
+			case v == 'O' && (ch == 'お' || ch == 'う'):
+				units = append(units, []string{"", "H", strings.ToUpper(passportVowels[ch])})
+				i++
+				continue
+
+			case v == 'U' && ch == 'う':
+				units = append(units, []string{""})
+				i++
+				continue
+
+			}
+		}
+
+		result, length, err := h2ppConv.Convert(string(runes[i:min(len(runes), c.maxLen()+i)]))
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case length == 0:
+			result, length = "", 1
+			if unicode.IsLetter(ch) {
+				result = string(ch)
+			}
+
+		case ch == 'を':
+			result = "o"
+
+		case ch == 'ん' && length == 1 && i+1 < len(runes):
+			next, _, err := h2ppConv.Convert(string(runes[i+1 : min(len(runes), c.maxLen()+i+1)]))
+			if err != nil {
+				return nil, err
+			}
+
+			if len(next) > 0 && strings.ContainsRune("bmp", rune(next[0])) {
+				result = "m"
+			}
+
+		}
+
+		units = append(units, []string{strings.ToUpper(result)})
+		i += length
+	}
+
+	return units, nil
+}
+
+// passportVowels are the vowels of the kana of the long vowels in the kana notation.
+var passportVowels = map[rune]string{'お': "o", 'う': "u"}
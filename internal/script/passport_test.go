@@ -0,0 +1,64 @@
+package script
+
+import "testing"
+
+func TestPassportName(t *testing.T) {
+	c, err := NewIConv(IConvConf{})
+	if err != nil {
+		t.Fatalf("NewIConv() error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		hira string
+		want [3]string // Hepburn, OH and kana notation
+	}{
+		{"おおの たろう", [3]string{"ONO TARO", "OHNO TAROH", "OONO TAROU"}},
+		{"かとう", [3]string{"KATO", "KATOH", "KATOU"}},
+		{"きょうこ", [3]string{"KYOKO", "KYOHKO", "KYOUKO"}},
+		{"ゆうこ", [3]string{"YUKO", "YUKO", "YUKO"}},
+		{"いのうえ", [3]string{"INOUE", "INOUE", "INOUE"}},
+		{"にいがた", [3]string{"NIIGATA", "NIIGATA", "NIIGATA"}},
+		{"なんば ほんま", [3]string{"NAMBA HOMMA", "NAMBA HOMMA", "NAMBA HOMMA"}},
+		{"しんいち", [3]string{"SHINICHI", "SHINICHI", "SHINICHI"}},
+		{"はっち", [3]string{"HATCHI", "HATCHI", "HATCHI"}},
+		{"ジョージ", [3]string{"JOJI", "JOJI", "JOJI"}},
+	} {
+		for notation, want := range tt.want {
+			got, err := c.PassportName(tt.hira, PassportNotation(notation))
+			if err != nil {
+				t.Errorf("IConv.PassportName(%q, %d) error: %v", tt.hira, notation, err)
+				continue
+			}
+
+			if got != want {
+				t.Errorf("IConv.PassportName(%q, %d) = %q, want %q", tt.hira, notation, got, want)
+			}
+		}
+	}
+}
+
+func TestPassportPattern(t *testing.T) {
+	c, err := NewIConv(IConvConf{})
+	if err != nil {
+		t.Fatalf("NewIConv() error: %v", err)
+	}
+
+	pattern, err := c.PassportPattern("おおの たろう")
+	if err != nil {
+		t.Fatalf("IConv.PassportPattern() error: %v", err)
+	}
+
+	for name, want := range map[string]bool{
+		"ONO TARO":   true,
+		"OHNO TARO":  true,
+		"OONO TAROU": true,
+		"OHNO TAROH": true,
+		"OUNO TARO":  false,
+		"ONO TARROU": false,
+		"ONOTARO":    false,
+	} {
+		if got := pattern.MatchString(name); got != want {
+			t.Errorf("IConv.PassportPattern(%q).MatchString(%q) = %t, want %t", "おおの たろう", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package script
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	CaseKeep     Case = iota // keep the case of the converted text
+	CaseLower                // lower case
+	CaseUpper                // upper case
+	CaseTitle                // upper case at the beginning of each word, lower case otherwise
+	CaseSentence             // upper case at the beginning of each sentence, lower case otherwise
+)
+
+const (
+	AttachSeparate Attachment = iota // separate the segments like any other segment
+	AttachJoin                       // join the segments to their neighbors without the separator
+	AttachDrop                       // drop the segments
+)
+
+// Case is the letter case of a rendered text, e.g. CaseTitle.
+type Case int
+
+// Attachment is how a class of segments is joined to the neighboring segments, e.g. AttachJoin.
+type Attachment int
+
+// RenderOptions are the options of (IConvertedSlice).Render.
+// The zero value concatenates the segments as converted.
+type RenderOptions struct {
+	Separator string // separator between the segments
+	Case      Case   // letter case of the text, which applies to the romanized systems
+	Trim      bool   // trim the whitespace of each segment, the segments left empty are dropped
+
+	// Whitespace is the attachment of the segments consisting of whitespace,
+	// which replace the separator if joined.
+	Whitespace Attachment
+
+	// Punctuation is the attachment of the segments consisting of symbols,
+	// which are joined to the preceding segment, or to the following one for opening brackets and quotes.
+	Punctuation Attachment
+}
+
+// Field returns the field of the converted segment corresponding to the output system, e.g. Hepburn for SystemHepburn.
+// It returns an empty string unless system is a single system.
+func (i IConverted) Field(system System) string {
+	if f := i.field(system); f != nil {
+		return *f
+	}
+
+	return ""
+}
+
+// field returns the pointer to the field corresponding to the output system, nil unless system is a single system.
+func (i *IConverted) field(system System) *string {
+	switch system {
+	case SystemHira:
+		return &i.Hira
+
+	case SystemKana:
+		return &i.Kana
+
+	case SystemHepburn:
+		return &i.Hepburn
+
+	case SystemKunrei:
+		return &i.Kunrei
+
+	case SystemPassport:
+		return &i.Passport
+
+	case SystemModifiedHepburn:
+		return &i.ModifiedHepburn
+
+	case SystemALALC:
+		return &i.ALALC
+
+	case SystemISO3602:
+		return &i.ISO3602
+
+	case SystemNihon:
+		return &i.Nihon
+
+	default:
+		return nil
+
+	}
+}
+
+// Render returns the text of the field corresponding to the output system, e.g. SystemKunrei,
+// with the segments joined and cased according to the options.
+func (i IConvertedSlice) Render(system System, opts RenderOptions) string {
+	var out strings.Builder
+
+	separate := false // a separator is due before the next segment
+	for _, v := range i {
+		text := v.Field(system)
+		if opts.Trim {
+			text = strings.TrimSpace(text)
+		}
+
+		if len(text) == 0 {
+			continue
+		}
+
+		attachment := AttachSeparate
+		switch {
+		case len(strings.TrimSpace(text)) == 0:
+			attachment = opts.Whitespace
+
+		case v.Script == ScriptSymbol:
+			attachment = opts.Punctuation
+
+		}
+
+		switch attachment {
+		case AttachDrop:
+			continue
+
+		case AttachJoin:
+			out.WriteString(text)
+			separate = len(strings.TrimSpace(text)) > 0 && !isOpening(text)
+			continue
+
+		}
+
+		if separate {
+			out.WriteString(opts.Separator)
+		}
+
+		out.WriteString(text)
+		separate = true
+	}
+
+	return applyCase(out.String(), opts.Case)
+}
+
+// Hiragana returns the text in hiragana, i.e. the Hira fields of the segments concatenated.
+func (i IConvertedSlice) Hiragana() string { return i.Render(SystemHira, RenderOptions{}) }
+
+// Katakana returns the text in katakana, i.e. the Kana fields of the segments concatenated.
+func (i IConvertedSlice) Katakana() string { return i.Render(SystemKana, RenderOptions{}) }
+
+// isOpening returns true if the text ends with an opening bracket or quote.
+func isOpening(text string) bool {
+	runes := []rune(text)
+	last := runes[len(runes)-1]
+	return unicode.In(last, unicode.Ps, unicode.Pi)
+}
+
+// applyCase returns the text in the letter case.
+func applyCase(text string, c Case) string {
+	switch c {
+	case CaseLower:
+		return strings.ToLower(text)
+
+	case CaseUpper:
+		return strings.ToUpper(text)
+
+	case CaseTitle, CaseSentence:
+		runes := []rune(strings.ToLower(text))
+		capitalize := true
+		for j, r := range runes {
+			switch {
+			case unicode.IsLetter(r) || unicode.IsNumber(r):
+				if capitalize {
+					runes[j] = unicode.ToUpper(r)
+				}
+
+				capitalize = false
+
+			case c == CaseTitle:
+				capitalize = capitalize || unicode.IsSpace(r) || unicode.In(r, unicode.Ps, unicode.Pi)
+
+			case strings.ContainsRune(".!?。！？", r):
+				capitalize = true
+
+			}
+		}
+
+		return string(runes)
+
+	default:
+		return text
+
+	}
+}
@@ -0,0 +1,53 @@
+package script
+
+import "testing"
+
+func TestIConvertedSlice_Render(t *testing.T) {
+	converted := IConvertedSlice{
+		{Orig: "「", Hira: "「", Kana: "「", Hepburn: "(", Script: ScriptSymbol},
+		{Orig: "今日", Hira: "きょう", Kana: "キョウ", Hepburn: "kyou", Script: ScriptKanji},
+		{Orig: "は", Hira: "は", Kana: "ハ", Hepburn: "ha", Script: ScriptHiragana},
+		{Orig: "」", Hira: "」", Kana: "」", Hepburn: ")", Script: ScriptSymbol},
+		{Orig: " ", Hira: " ", Kana: " ", Hepburn: " ", Script: ScriptAlpha},
+		{Orig: "Go", Hira: "Go", Kana: "Go", Hepburn: "Go", Script: ScriptAlpha},
+		{Orig: "です。", Hira: "です。", Kana: "デス。", Hepburn: "desu.", Script: ScriptHiragana},
+		{Orig: "日本", Hira: "にほん", Kana: "ニホン", Hepburn: "nihon", Script: ScriptKanji},
+	}
+
+	joined := RenderOptions{Separator: " ", Whitespace: AttachJoin, Punctuation: AttachJoin}
+	withCase := func(c Case) RenderOptions { o := joined; o.Case = c; return o }
+
+	for _, tt := range []struct {
+		name   string
+		system System
+		opts   RenderOptions
+		want   string
+	}{
+		{"concatenated", SystemHepburn, RenderOptions{}, "(kyouha) Godesu.nihon"},
+		{"separated", SystemHepburn, RenderOptions{Separator: " "}, "( kyou ha )   Go desu. nihon"},
+		{"joined", SystemHepburn, joined, "(kyou ha) Go desu. nihon"},
+		{"dropped", SystemHepburn, RenderOptions{Separator: " ", Whitespace: AttachDrop, Punctuation: AttachDrop}, "kyou ha Go desu. nihon"},
+		{"trimmed", SystemHepburn, RenderOptions{Separator: "-", Trim: true}, "(-kyou-ha-)-Go-desu.-nihon"},
+		{"lower", SystemHepburn, withCase(CaseLower), "(kyou ha) go desu. nihon"},
+		{"upper", SystemHepburn, withCase(CaseUpper), "(KYOU HA) GO DESU. NIHON"},
+		{"title", SystemHepburn, withCase(CaseTitle), "(Kyou Ha) Go Desu. Nihon"},
+		{"sentence", SystemHepburn, withCase(CaseSentence), "(Kyou ha) go desu. Nihon"},
+		{"hira", SystemHira, RenderOptions{}, "「きょうは」 Goです。にほん"},
+		{"kana", SystemKana, joined, "「キョウ ハ」 Go デス。 ニホン"},
+		{"invalid", SystemAll, joined, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := converted.Render(tt.system, tt.opts); got != tt.want {
+				t.Errorf("IConvertedSlice.Render(%v, %+v) = %q, want %q", tt.system, tt.opts, got, tt.want)
+			}
+		})
+	}
+
+	if got, want := converted.Hiragana(), "「きょうは」 Goです。にほん"; got != want {
+		t.Errorf("IConvertedSlice.Hiragana() = %q, want %q", got, want)
+	}
+
+	if got, want := converted.Katakana(), "「キョウハ」 Goデス。ニホン"; got != want {
+		t.Errorf("IConvertedSlice.Katakana() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+package script
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/sarumaj/go-kakasi/internal/codegen"
+	"github.com/sarumaj/go-kakasi/internal/properties"
+)
+
+// The dictionaries are immutable, thus they are loaded once on first use and shared by all converters.
+var (
+	jisyoHalfkana     = sync.OnceValues(properties.Configurations.JisyoHalfkana)
+	jisyoHepburn      = sync.OnceValues(properties.Configurations.JisyoHepburn)
+	jisyoHepburnHira  = sync.OnceValues(properties.Configurations.JisyoHepburnHira)
+	jisyoKunrei       = sync.OnceValues(properties.Configurations.JisyoKunrei)
+	jisyoKunreiHira   = sync.OnceValues(properties.Configurations.JisyoKunreiHira)
+	jisyoNihon        = sync.OnceValues(properties.Configurations.JisyoNihon)
+	jisyoNihonHira    = sync.OnceValues(properties.Configurations.JisyoNihonHira)
+	jisyoPassport     = sync.OnceValues(properties.Configurations.JisyoPassport)
+	jisyoPassportHira = sync.OnceValues(properties.Configurations.JisyoPassportHira)
+)
+
+// Preload loads the dictionaries shared by all converters.
+func Preload() error {
+	var errs []error
+	for _, load := range []func() (*codegen.LookupMap, error){
+		jisyoHalfkana,
+		jisyoHepburn,
+		jisyoHepburnHira,
+		jisyoKunrei,
+		jisyoKunreiHira,
+		jisyoNihon,
+		jisyoNihonHira,
+		jisyoPassport,
+		jisyoPassportHira,
+	} {
+		_, err := load()
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,215 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/sarumaj/go-kakasi/internal/properties"
+)
+
+// spelling are the rules of a romanization method, which join a syllable with the following kana.
+// Unlike the dictionaries, which convert each syllable on its own, the rules join a syllable with the following vowel,
+// unless they are separated by a word boundary, e.g. 東京 is read Tōkyō, while 思う is read omou.
+type spelling struct {
+	pairs     map[[2]rune]string // romanized vowel followed by a vowel kana, e.g. o and う to ō
+	marks     map[rune]string    // romanized vowel followed by a long mark, e.g. a and ー to ā
+	syllabicN string             // syllabic n followed by a vowel, e.g. n' of ken'i
+	beforeY   bool               // the syllabic n is separated from a following y as well, e.g. kin'you
+}
+
+// spellings are the rules of the methods.
+// Hepburn, Kunrei and Passport spell the vowel kana as they are written and double the vowels of the long marks.
+// Modified Hepburn and ALA-LC mark the long vowels with macrons, but keep い+い as ii and え+い as ei.
+// ALA-LC writes the long i of a long mark as ii as well.
+// ISO 3602 strict marks the long vowels with circumflexes.
+// Nihon-shiki spells the vowel kana as they are written, only the long marks are circumflexes, which keeps it reversible.
+// Passport writes the syllabic n as a plain n, the other methods separate it from a following vowel by an apostrophe,
+// Nihon-shiki from a following y as well, which keeps it reversible.
+var spellings = map[method]spelling{
+	MethodHepburn:  {marks: longMarks[LongMarkDouble], syllabicN: "n'"},
+	MethodKunrei:   {marks: longMarks[LongMarkDouble], syllabicN: "n'"},
+	MethodPassport: {marks: longMarks[LongMarkDouble], syllabicN: "n"},
+	MethodModifiedHepburn: {
+		pairs:     map[[2]rune]string{{'a', 'あ'}: "ā", {'u', 'う'}: "ū", {'e', 'え'}: "ē", {'o', 'お'}: "ō", {'o', 'う'}: "ō"},
+		marks:     longMarks[LongMarkMacron],
+		syllabicN: "n'",
+	},
+	MethodALALC: {
+		pairs:     map[[2]rune]string{{'a', 'あ'}: "ā", {'u', 'う'}: "ū", {'e', 'え'}: "ē", {'o', 'お'}: "ō", {'o', 'う'}: "ō"},
+		marks:     map[rune]string{'a': "ā", 'i': "ii", 'u': "ū", 'e': "ē", 'o': "ō"},
+		syllabicN: "n'",
+	},
+	MethodNihon: {marks: longMarks[LongMarkCircumflex], syllabicN: "n'", beforeY: true},
+	MethodISO3602: {
+		pairs:     map[[2]rune]string{{'a', 'あ'}: "â", {'u', 'う'}: "û", {'e', 'え'}: "ê", {'o', 'お'}: "ô", {'o', 'う'}: "ô"},
+		marks:     longMarks[LongMarkCircumflex],
+		syllabicN: "n'",
+	},
+}
+
+var (
+	// hepburnOverrides are the conversions of Modified Hepburn and ALA-LC which differ from the Hepburn dictionary.
+	hepburnOverrides = map[string]string{"を": "o"}
+
+	// iso3602Overrides are the conversions of ISO 3602 strict which differ from the Kunrei dictionary,
+	// it keeps the distinctions of Nihon-shiki.
+	iso3602Overrides = map[string]string{
+		"ぢ": "di", "づ": "du", "ぢゃ": "dya", "ぢゅ": "dyu", "ぢょ": "dyo",
+		"っぢ": "ddi", "っづ": "ddu", "っぢゃ": "ddya", "っぢゅ": "ddyu", "っぢょ": "ddyo",
+		"ゐ": "wi", "ゑ": "we", "を": "wo",
+	}
+)
+
+// overridesMaxLen is the length of the longest key of the overrides in characters.
+const overridesMaxLen = 3
+
+// newSpelling returns the rules of the method with the rendering of the style applied.
+func newSpelling(m method, style Style) (*spelling, error) {
+	s, ok := spellings[m]
+	if !ok {
+		return nil, fmt.Errorf("invalid method: %s", m)
+	}
+
+	if style.SyllabicN != SyllabicNDefault {
+		if s.syllabicN, ok = syllabicNs[style.SyllabicN]; !ok {
+			return nil, fmt.Errorf("invalid syllabic n: %d", style.SyllabicN)
+		}
+	}
+
+	s.beforeY = s.beforeY || style.SyllabicNBeforeY
+	if style.LongMark != LongMarkDefault {
+		if s.marks, ok = longMarks[style.LongMark]; !ok {
+			return nil, fmt.Errorf("invalid long mark: %d", style.LongMark)
+		}
+	}
+
+	return &s, nil
+}
+
+// join returns the long vowel of the romanized vowel followed by the kana, if any.
+func (s spelling) join(vowel, next rune) (string, bool) {
+	if properties.Ch.IsLongSymbol(next) {
+		v, ok := s.marks[vowel]
+		return v, ok
+	}
+
+	v, ok := s.pairs[[2]rune{vowel, next}]
+	return v, ok
+}
+
+// romanize converts the hiragana to romaji like convert, joining the syllables by the rules of the converter.
+// The vowels are not joined across the boundary, i.e. the offset of the okurigana in the hiragana.
+// The other characters are converted by the symbol converter, which unlike for the other modes
+// is not applied to the romaji, since it would strip the macrons and circumflexes.
+func (c IConv) romanize(hira string, boundary int, conv *Hira) (string, error) {
+	var out []rune
+
+	kana := false // the last character of the output is a romanized kana
+	runes := []rune(hira)
+	for i := 0; i < len(runes); {
+		if kana && i != boundary {
+			if long, ok := conv.spelling.join(out[len(out)-1], runes[i]); ok {
+				out = append(out[:len(out)-1], []rune(long)...)
+				i++
+				continue
+			}
+		}
+
+		result, length, err := conv.Convert(string(runes[i:min(len(runes), c.maxLen()+i)]))
+		if err != nil {
+			return "", err
+		}
+
+		if length > 0 {
+			// the syllabic n is separated from a following vowel, e.g. ken'i unlike keni (けに), and y if enabled
+			if length == 1 && runes[i] == 'ん' && i+1 < len(runes) {
+				next, _, err := conv.Convert(string(runes[i+1 : min(len(runes), c.maxLen()+i+1)]))
+				if err != nil {
+					return "", err
+				}
+
+				if len(next) > 0 && (strings.ContainsRune("aiueo", rune(next[0])) || conv.spelling.beforeY && next[0] == 'y') {
+					result = conv.spelling.syllabicN
+				}
+			}
+
+			out = append(out, []rune(result)...)
+			kana, i = len(result) > 0, i+length
+			continue
+		}
+
+		symbol, err := c.convert(string(runes[i]), c.s2aConv)
+		if err != nil {
+			return "", err
+		}
+
+		// a long symbol not joined to a vowel repeats the last character
+		if properties.Ch.IsLongSymbol(runes[i]) && len(out) > 0 {
+			symbol = string(out[len(out)-1])
+		}
+
+		out = append(out, []rune(symbol)...)
+		kana, i = false, i+1
+	}
+
+	return string(out), nil
+}
+
+// okuriganaOffset returns the offset of the okurigana in the hiragana reading of the text,
+// i.e. of the suffix beginning with hiragana the text and its reading share, or -1 if they share none.
+// If the text is its own reading, the offset is the one of the ending of a verb written in kana, see kanaVerbOffset.
+func okuriganaOffset(text, hira string) int {
+	t, h := []rune(text), []rune(hira)
+
+	n := 0
+	for n < len(t) && n < len(h) && t[len(t)-1-n] == h[len(h)-1-n] {
+		n++
+	}
+
+	// the okurigana begin with hiragana, e.g. not with the long marks of katakana words
+	for n > 0 && !unicode.Is(unicode.Hiragana, t[len(t)-n]) {
+		n--
+	}
+
+	switch {
+	case text == hira:
+		return kanaVerbOffset(h)
+
+	case n == 0 || n == len(t):
+		return -1
+
+	}
+
+	return len(h) - n
+}
+
+// longVowelWords are common words written in kana, which end in a long vowel rather than in the ending of a verb.
+var longVowelWords = map[string]bool{"ありがとう": true, "おはよう": true, "ごちそう": true, "いちおう": true, "さよう": true}
+
+// kanaVerbOffset returns the offset of the trailing う of the last word of the hiragana if it is the ending of a verb, e.g. おもう,
+// or -1 otherwise. A trailing う following a syllable of the o or u row of a word of three characters at least is an ending,
+// unless the word contains a small kana, っ, ん, a long mark or another う, which are common in Sino-Japanese and loan words,
+// e.g. がっこう and ふうとう, or it is a common word with a long vowel, see longVowelWords.
+func kanaVerbOffset(hira []rune) int {
+	end := len(hira)
+	for end > 0 && !unicode.Is(unicode.Hiragana, hira[end-1]) {
+		end--
+	}
+
+	start := end
+	for start > 0 && unicode.Is(unicode.Hiragana, hira[start-1]) {
+		start--
+	}
+
+	word := hira[start:end]
+	if len(word) < 3 || word[len(word)-1] != 'う' || !strings.ContainsRune("おこそとのほもよろごぞどぼぽうくすつぬふむゆるぐずづぶぷ", word[len(word)-2]) {
+		return -1
+	}
+
+	if strings.ContainsAny(string(word[:len(word)-1]), "ぁぃぅぇぉゃゅょゎっんーう") || longVowelWords[string(word)] {
+		return -1
+	}
+
+	return end - 1
+}
@@ -0,0 +1,49 @@
+package script
+
+const (
+	SyllabicNDefault    SyllabicN = iota // rendering of the method, e.g. n' for Hepburn and n for Passport
+	SyllabicNApostrophe                  // apostrophe, e.g. ken'i
+	SyllabicNHyphen                      // hyphen, e.g. ken-i
+	SyllabicNDouble                      // doubled n, e.g. kenni
+	SyllabicNPlain                       // plain n, e.g. keni
+)
+
+const (
+	LongMarkDefault    LongMark = iota // rendering of the method, e.g. the doubled vowel for Hepburn and the macron for Modified Hepburn
+	LongMarkDouble                     // doubled vowel, e.g. koohii
+	LongMarkMacron                     // macron, e.g. kōhī
+	LongMarkCircumflex                 // circumflex, e.g. kôhî
+	LongMarkHyphen                     // hyphen, e.g. ko-hi-
+	LongMarkDrop                       // dropped, e.g. kohi
+)
+
+// SyllabicN is the rendering of the syllabic n (ん) followed by a vowel, e.g. SyllabicNApostrophe.
+type SyllabicN int
+
+// LongMark is the rendering of a vowel followed by the long mark (ー), e.g. LongMarkMacron.
+type LongMark int
+
+// Style is the rendering of the syllabic n and the long marks of a romanization system.
+// The zero value keeps the rendering of the method.
+type Style struct {
+	SyllabicN        SyllabicN
+	SyllabicNBeforeY bool // render the syllabic n followed by y as well, e.g. kin'you, which Nihon-shiki always does
+	LongMark         LongMark
+}
+
+// syllabicNs are the spellings of the syllabic n followed by a vowel.
+var syllabicNs = map[SyllabicN]string{
+	SyllabicNApostrophe: "n'",
+	SyllabicNHyphen:     "n-",
+	SyllabicNDouble:     "nn",
+	SyllabicNPlain:      "n",
+}
+
+// longMarks are the spellings of the vowels followed by the long mark.
+var longMarks = map[LongMark]map[rune]string{
+	LongMarkDouble:     {'a': "aa", 'i': "ii", 'u': "uu", 'e': "ee", 'o': "oo"},
+	LongMarkMacron:     {'a': "ā", 'i': "ī", 'u': "ū", 'e': "ē", 'o': "ō"},
+	LongMarkCircumflex: {'a': "â", 'i': "î", 'u': "û", 'e': "ê", 'o': "ô"},
+	LongMarkHyphen:     {'a': "a-", 'i': "i-", 'u': "u-", 'e': "e-", 'o': "o-"},
+	LongMarkDrop:       {'a': "a", 'i': "i", 'u': "u", 'e': "e", 'o': "o"},
+}
@@ -0,0 +1,59 @@
+package script
+
+import "testing"
+
+func TestStyle(t *testing.T) {
+	for _, tt := range []struct {
+		system System
+		style  Style
+		hira   string
+		want   string
+	}{
+		{SystemHepburn, Style{}, "けんい", "ken'i"},
+		{SystemHepburn, Style{}, "きんよう", "kinyou"},
+		{SystemKunrei, Style{}, "ほんや", "honya"},
+		{SystemHepburn, Style{SyllabicNBeforeY: true}, "きんよう", "kin'you"},
+		{SystemKunrei, Style{SyllabicN: SyllabicNHyphen, SyllabicNBeforeY: true}, "ほんや", "hon-ya"},
+		{SystemNihon, Style{}, "きんよう", "kin'you"},
+		{SystemHepburn, Style{}, "こーひー", "koohii"},
+		{SystemPassport, Style{}, "けんい", "keni"},
+		{SystemHepburn, Style{SyllabicN: SyllabicNHyphen}, "けんい", "ken-i"},
+		{SystemHepburn, Style{SyllabicN: SyllabicNDouble}, "けんい", "kenni"},
+		{SystemHepburn, Style{SyllabicN: SyllabicNPlain}, "けんい", "keni"},
+		{SystemPassport, Style{SyllabicN: SyllabicNApostrophe}, "けんい", "ken'i"},
+		{SystemHepburn, Style{SyllabicN: SyllabicNHyphen}, "かんたん", "kantan"},
+		{SystemHepburn, Style{LongMark: LongMarkMacron}, "こーひー", "kōhī"},
+		{SystemKunrei, Style{LongMark: LongMarkCircumflex}, "らーめん", "râmen"},
+		{SystemHepburn, Style{LongMark: LongMarkHyphen}, "こーひー", "ko-hi-"},
+		{SystemPassport, Style{LongMark: LongMarkDrop}, "こーひー", "kohi"},
+		{SystemModifiedHepburn, Style{LongMark: LongMarkDouble}, "こーひー", "koohii"},
+		{SystemModifiedHepburn, Style{LongMark: LongMarkDouble}, "とうきょう", "tōkyō"},
+		{SystemALALC, Style{}, "ぱーてぃー", "pāteii"},
+		{SystemALALC, Style{LongMark: LongMarkMacron}, "ぱーてぃー", "pāteī"},
+		{SystemNihon, Style{SyllabicN: SyllabicNHyphen, LongMark: LongMarkDouble}, "きんよう", "kin-you"},
+	} {
+		c, err := NewIConv(IConvConf{Systems: tt.system, Styles: map[System]Style{tt.system: tt.style}})
+		if err != nil {
+			t.Errorf("NewIConv() error: %v", err)
+			continue
+		}
+
+		got, err := c.Convert(tt.hira, tt.hira)
+		if err != nil {
+			t.Errorf("IConv.Convert(%q, %q) error: %v", tt.hira, tt.hira, err)
+			continue
+		}
+
+		if got := got.Field(tt.system); got != tt.want {
+			t.Errorf("IConv.Convert(%q, %q) = %q, want %q (%+v)", tt.hira, tt.hira, got, tt.want, tt.style)
+		}
+	}
+}
+
+func TestStyleInvalid(t *testing.T) {
+	for _, style := range []Style{{SyllabicN: 42}, {LongMark: 42}} {
+		if _, err := NewIConv(IConvConf{Systems: SystemHepburn, Styles: map[System]Style{SystemHepburn: style}}); err == nil {
+			t.Errorf("NewIConv() with style %+v: want error", style)
+		}
+	}
+}
@@ -1,10 +1,12 @@
 package kakasi
 
 import (
+	"context"
+	"errors"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/sarumaj/go-kakasi/internal/kanji"
-	"github.com/sarumaj/go-kakasi/internal/properties"
 	"github.com/sarumaj/go-kakasi/internal/script"
 
 	"golang.org/x/text/unicode/norm"
@@ -27,186 +29,290 @@ var (
 
 type chType int
 
+// cancelCheckInterval is the number of scanning steps between the checks of the cancellation of the context.
+const cancelCheckInterval = 1024
+
+const (
+	ScriptKanji             = script.ScriptKanji
+	ScriptHiragana          = script.ScriptHiragana
+	ScriptKatakana          = script.ScriptKatakana
+	ScriptHalfwidthKatakana = script.ScriptHalfwidthKatakana
+	ScriptAlpha             = script.ScriptAlpha
+	ScriptSymbol            = script.ScriptSymbol
+	ScriptMixed             = script.ScriptMixed
+)
+
+// Script is the class of the characters of a converted segment, e.g. ScriptKanji.
+type Script = script.Script
+
 // IConverted is a type that represents a converted text.
 type IConverted = script.IConverted
 
 // IConvertedSlice is a slice of IConverted.
 type IConvertedSlice = script.IConvertedSlice
 
+const (
+	CaseKeep     = script.CaseKeep
+	CaseLower    = script.CaseLower
+	CaseUpper    = script.CaseUpper
+	CaseTitle    = script.CaseTitle
+	CaseSentence = script.CaseSentence
+)
+
+// Case is the letter case of a rendered text, e.g. CaseTitle.
+type Case = script.Case
+
+const (
+	AttachSeparate = script.AttachSeparate
+	AttachJoin     = script.AttachJoin
+	AttachDrop     = script.AttachDrop
+)
+
+// Attachment is how a class of segments is joined to the neighboring segments, e.g. AttachJoin.
+type Attachment = script.Attachment
+
+// RenderOptions are the options of (IConvertedSlice).Render.
+type RenderOptions = script.RenderOptions
+
+// FuriganaFormatter writes the segments of a converted text with furigana, see (IConvertedSlice).FuriganizeWith.
+type FuriganaFormatter = script.FuriganaFormatter
+
+// Ruby is a base text annotated with its reading.
+type Ruby = script.Ruby
+
+// FuriganaBrackets writes the readings in brackets following the base text, e.g. 漢字[かんじ].
+type FuriganaBrackets = script.FuriganaBrackets
+
+// FuriganaHTML writes HTML ruby elements, optionally annotated with romaji.
+type FuriganaHTML = script.FuriganaHTML
+
+// FuriganaAozora writes the ruby notation of Aozora Bunko, e.g. ｜漢字《かんじ》.
+type FuriganaAozora = script.FuriganaAozora
+
+// FuriganaLaTeX writes the ruby commands of the pxrubrica package, e.g. \ruby{漢字}{かんじ}.
+type FuriganaLaTeX = script.FuriganaLaTeX
+
+// FuriganaAnki writes the furigana notation of Anki, e.g. 漢字[かんじ] preceded by a space.
+type FuriganaAnki = script.FuriganaAnki
+
+// Aligner splits a ruby into the rubies of its parts, see (IConvertedSlice).FuriganizeAligned.
+type Aligner = script.Aligner
+
+// OkuriganaAligner leaves the kana of the base text outside of the rubies, e.g. 助[たす]け.
+type OkuriganaAligner = script.OkuriganaAligner
+
+// MonoAligner splits the readings of the runs of kanji per character, e.g. 確[かく]定[てい]す, see (Kakasi).MonoAligner.
+type MonoAligner = script.MonoAligner
+
 // Kakasi is a type that represents a Japanese text converter.
+// The dictionaries are immutable after loading, thus a Kakasi is safe for concurrent use by multiple goroutines.
 type Kakasi struct {
 	iConv *script.IConv
 	jConv *kanji.JConv
+	opts  options
 }
 
 // Convert converts the input text to kana/romaji.
 func (k Kakasi) Convert(text string) (IConvertedSlice, error) {
-	if len([]rune(text)) == 0 {
+	return k.ConvertContext(context.Background(), text)
+}
+
+// ConvertContext converts the input text to kana/romaji.
+// The cancellation of the context is checked between segments and periodically while scanning,
+// in which case the conversion is aborted and the context error returned.
+// If the input text exceeds the limit set by WithMaxInputRunes, ErrInputTooLarge is returned.
+func (k Kakasi) ConvertContext(ctx context.Context, text string) (IConvertedSlice, error) {
+	if err := k.checkInputSize(utf8.RuneCountInString(text)); err != nil {
+		return nil, err
+	}
+
+	if len(text) == 0 {
 		return IConvertedSlice{{}}, nil
 	}
 
-	var originalText, kanaText string
+	runes, spans := decodeSpans([]byte(text), 0, 0)
+	if k.opts.normalization {
+		runes, spans, _ = normalizeSpans(runes, spans, true)
+	}
+
 	var results IConvertedSlice
-	var fBuffer bool // output buffer flag
-	var fText bool   // output text flag
-	var fCpInc bool  // output copy and increment flag
-	for i, t := 0, chKanji; i < len([]rune(text)); {
-		switch ch := []rune(text)[i]; {
+	_, err := k.convert(ctx, runes, spans, true, func(result IConverted) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		case properties.Ch.IsEndmark(ch):
-			fBuffer, fText, fCpInc, t = true, true, true, chSymbol
+	return results, nil
+}
 
-		case properties.Ch.IsLongSymbol(ch):
-			fBuffer, fText, fCpInc = false, false, true
+// MonoAligner returns the aligner of the furigana per kanji character (mono-ruby),
+// which splits the readings by the readings of the single characters in the dictionaries, including the user dictionary.
+func (k Kakasi) MonoAligner() MonoAligner { return MonoAligner{Readings: k.jConv.Readings} }
 
-		case symbol.IsRegion(ch):
-			fBuffer, fText, fCpInc, t = t != chSymbol, t == chSymbol, true, chSymbol
+// checkInputSize returns ErrInputTooLarge if the number of characters exceeds the configured limit.
+func (k Kakasi) checkInputSize(size int) error {
+	if k.opts.maxInputRunes > 0 && size > k.opts.maxInputRunes {
+		return &ErrInputTooLarge{Limit: k.opts.maxInputRunes, Size: size}
+	}
 
-		case kata.IsRegion(ch):
-			fBuffer, fText, fCpInc, t = t != chKana, false, true, chKana
+	return nil
+}
 
-		case hira.IsRegion(ch):
-			fBuffer, fText, fCpInc, t = t != chHiragana, false, true, chHiragana
+// normalizeSymbol standardizes long symbols, dashes and quotation marks.
+// It is applied to the input text before the NFKC normalization.
+func normalizeSymbol(r rune) rune {
+	switch r {
+	case '〜', '～', '﹣', '－', '—', '━', '─':
+		return 'ー'
 
-		case alpha.IsRegion(ch):
-			fBuffer, fText, fCpInc, t = t != chAlpha, false, true, chAlpha
+	case '’':
+		return '\''
 
-		case k.jConv.IsRegion(ch):
-			if len([]rune(originalText)) > 0 {
-				result, err := k.iConv.Convert(originalText, kanaText)
-				if err == nil {
-					results = append(results, *result)
-				}
-			}
+	case '”', '“':
+		return '"'
 
-			converted, length, _ := k.jConv.Convert(string([]rune(text)[i:]), originalText)
-			t = chKanji
+	case '―', '‐', '˗', '֊', '‑', '‒', '–', '⁃', '⁻', '₋', '−':
+		return '-'
 
-			if length > 0 {
-				originalText = string([]rune(text)[i : i+length])
-				kanaText = converted
-				i += length
-				fBuffer, fText, fCpInc = false, false, false
-
-			} else { // unknown kanji
-				originalText = string([]rune(text)[i])
-				kanaText = ""
-				i++
-				fBuffer, fText, fCpInc = true, false, false
+	}
 
-			}
+	return r
+}
 
-		case 0xF000 <= ch && ch <= 0xFFFD, 0x10000 <= ch && ch <= 0x10FFFD: // PUA, ignore and drop
-			if len([]rune(originalText)) > 0 {
-				result, err := k.iConv.Convert(originalText, kanaText)
-				if err == nil {
-					results = append(results, *result)
-				}
-			}
-			i++
-			fBuffer, fText, fCpInc = false, false, false
-
-		default:
-			if len([]rune(originalText)) > 0 {
-				result, err := k.iConv.Convert(originalText, kanaText)
-				if err == nil {
-					results = append(results, *result)
-				}
-			}
+// Normalize normalizes the input text.
+// It converts the input text to NFKC and standardizes long symbols.
+func (Kakasi) Normalize(text string) (string, error) {
+	return norm.NFKC.String(strings.Map(normalizeSymbol, text)), nil
+}
 
-			result, err := k.iConv.Convert(string([]rune(text)[i]), "")
-			if err == nil {
-				results = append(results, *result)
-			}
+// convert scans the text and passes the converted segments to yield.
+// The spans map the characters of the text to the input text.
+// Unless atEOF, the scanning stops before the scanner would look beyond the end of the text,
+// and only the segments completed by the scanner are converted, the pending segment is left.
+// It returns the number of characters of the text covered by the converted segments.
+func (k Kakasi) convert(ctx context.Context, text []rune, spans []span, atEOF bool, yield func(IConverted) error) (int, error) {
+	return k.convertSegments(ctx, k.newScanner(text), spans, atEOF, func(result IConverted, _ segment) error {
+		return yield(result)
+	})
+}
 
-			i++
-			fBuffer, fText, fCpInc = false, false, false
+// newScanner returns a scanner of the text in the segmentation mode of the instance.
+func (k Kakasi) newScanner(text []rune) *scanner {
+	return &scanner{jConv: k.jConv, text: text, t: chKanji, lattice: k.opts.segmentation == SegmentationLattice}
+}
 
+// convertSegment converts a segment of the text, romanizing its particles as they are read if enabled.
+func (k Kakasi) convertSegment(text []rune, seg segment) (*IConverted, error) {
+	if k.opts.particles && !seg.dictionary && seg.kana == string(text[seg.start:seg.end]) {
+		if particles := k.particles(text, seg); len(particles) > 0 {
+			return k.iConv.ConvertParticles(seg.kana, particles)
 		}
+	}
 
-		// convert to kana and output based on flags
-		switch {
-		case fBuffer && fText:
-			originalText += string([]rune(text)[i])
-			kanaText += string([]rune(text)[i])
+	return k.iConv.Convert(string(text[seg.start:seg.end]), seg.kana)
+}
 
-			result, err := k.iConv.Convert(originalText, kanaText)
-			if err == nil {
-				results = append(results, *result)
-			}
+// convertSegments is convert with a configured scanner, which passes each converted segment along with its result.
+// The scanner continues at its position, thus it may resume on the text extended by the next chunk of a stream.
+func (k Kakasi) convertSegments(ctx context.Context, s *scanner, spans []span, atEOF bool, yield func(IConverted, segment) error) (int, error) {
+	text := s.text
 
-			originalText, kanaText = "", ""
-			i++
+	var completed []segment
+	collect := func(seg segment) { completed = append(completed, seg) }
 
-		case fBuffer && fCpInc:
-			if len([]rune(originalText)) > 0 {
-				result, err := k.iConv.Convert(originalText, kanaText)
-				if err == nil {
-					results = append(results, *result)
-				}
+	// commit converts the completed segments and passes them to yield
+	// in strict mode, conversion errors are returned, otherwise the affected segments are dropped
+	commit := func() error {
+		for _, seg := range completed {
+			if err := ctx.Err(); err != nil {
+				return err
 			}
-			originalText, kanaText = string([]rune(text)[i]), string([]rune(text)[i])
-			i++
 
-		case fCpInc:
-			originalText += string([]rune(text)[i])
-			kanaText += string([]rune(text)[i])
-			i++
+			result, err := k.convertSegment(text, seg)
+			switch {
+			case err == nil:
+				converted := *result
+				converted.Script, converted.Dictionary = s.classify(text[seg.start:seg.end]), seg.dictionary
+				converted.Ambiguous = seg.ambiguous
+				converted.Start, converted.ByteStart = spans[seg.start].start, spans[seg.start].byteStart
+				converted.End, converted.ByteEnd = spans[seg.end-1].end, spans[seg.end-1].byteEnd
+				if err := yield(converted, seg); err != nil {
+					return err
+				}
 
+			case k.opts.strict:
+				return err
+
+			}
 		}
+
+		completed = completed[:0]
+		return nil
 	}
 
-	if len([]rune(originalText)) > 0 {
-		result, err := k.iConv.Convert(originalText, kanaText)
-		if err == nil {
-			results = append(results, *result)
+	// the segments emitted by the scanner are final, thus they are committed right away
+	// the context is checked periodically as well, since a long segment is scanned before it is committed
+	limit := len(text) - s.lookahead()
+	for i := 0; s.pos < len(text) && (atEOF || s.pos <= limit); i++ {
+		if i%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return s.start, err
+			}
+		}
+
+		s.scan(collect)
+		if err := commit(); err != nil {
+			return s.start, err
 		}
 	}
 
-	return results, nil
-}
+	if !atEOF {
+		return s.start, nil
+	}
 
-// Normalize normalizes the input text.
-// It converts the input text to NFKC and standardizes long symbols.
-func (Kakasi) Normalize(text string) (string, error) {
-	text = strings.NewReplacer(
-		"〜", "ー",
-		"～", "ー",
-		"’", "'",
-		"”", "\"",
-		"“", "\"",
-		"―", "-",
-		"‐", "-",
-		"˗", "-",
-		"֊", "-",
-		"‐", "-",
-		"‑", "-",
-		"‒", "-",
-		"–", "-",
-		"⁃", "-",
-		"⁻", "-",
-		"₋", "-",
-		"−", "-",
-		"﹣", "ー",
-		"－", "ー",
-		"—", "ー",
-		"―", "ー",
-		"━", "ー",
-		"─", "ー",
-	).Replace(text)
-	return norm.Form(norm.NFKC).String(text), nil
+	s.flush(collect)
+	if err := commit(); err != nil {
+		return s.start, err
+	}
+
+	return len(text), nil
 }
 
-func NewKakasi() (*Kakasi, error) {
-	iConv, err := script.NewIConv()
+// NewKakasi creates a new Kakasi instance.
+// The dictionaries are loaded on the first call and shared by all instances,
+// while the conversion caches and the options belong to each instance.
+// By default, the output systems of SystemDefault are computed and the conversion caches are enabled.
+// The behavior can be tuned with functional options, e.g.:
+//
+//	k, err := kakasi.NewKakasi(kakasi.WithSystems(kakasi.SystemHira, kakasi.SystemHepburn))
+func NewKakasi(opts ...Option) (*Kakasi, error) {
+	o := newOptions(opts...)
+
+	iConv, err := script.NewIConv(script.IConvConf{Systems: o.systems, Styles: o.styles, CacheSize: o.iConvCacheSize})
 	if err != nil {
 		return nil, err
 	}
 
-	jConv, err := kanji.NewJConv()
+	jConv, err := kanji.NewJConv(o.jConvCacheSize)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Kakasi{iConv: iConv, jConv: jConv}, nil
+	k := &Kakasi{iConv: iConv, jConv: jConv, opts: o}
+	for _, f := range o.userDictFiles {
+		if err := k.loadDictionaryFile(f.path, f.load); err != nil {
+			return nil, err
+		}
+	}
+
+	return k, nil
+}
+
+// Preload loads the dictionaries shared by all Kakasi instances.
+// Otherwise, the dictionaries are loaded by the first call of NewKakasi.
+// It is meant to be called at the start of a program to avoid the latency of the first conversion.
+func Preload() error {
+	return errors.Join(kanji.Preload(), script.Preload())
 }
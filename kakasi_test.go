@@ -1,14 +1,28 @@
 package kakasi
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/sarumaj/go-kakasi/internal/script"
 )
 
+// ignoreAnnotations ignores the annotations and the offsets of the converted segments, which are tested separately.
+var ignoreAnnotations = cmp.FilterPath(func(p cmp.Path) bool {
+	switch p.Last().String() {
+	case ".Script", ".Dictionary", ".Ambiguous", ".Start", ".End", ".ByteStart", ".ByteEnd":
+		return true
+	}
+
+	return false
+}, cmp.Ignore())
+
 func TestKakasi(t *testing.T) {
 	testID := 1
 	for _, tt := range []struct {
@@ -82,6 +96,11 @@ func TestKakasi(t *testing.T) {
 			{Orig: "ﾞ", Hira: "゛", Kana: "ﾞ", Hepburn: "\"", Kunrei: "゛", Passport: "゛"},
 			{Orig: "っ、", Hira: "っ、", Kana: "ッ、", Hepburn: "tsu,", Kunrei: "tu,", Passport: "tsu,"},
 		}},
+		{"漢字\n漢字", script.IConvertedSlice{
+			{Orig: "漢字", Hira: "かんじ", Kana: "カンジ", Hepburn: "kanji", Kunrei: "kanzi", Passport: "kanji"},
+			{Orig: "\n"},
+			{Orig: "漢字", Hira: "かんじ", Kana: "カンジ", Hepburn: "kanji", Kunrei: "kanzi", Passport: "kanji"},
+		}},
 		{"藍之介", script.IConvertedSlice{{Orig: "藍之介", Hira: "あいのすけ", Kana: "アイノスケ", Hepburn: "ainosuke", Kunrei: "ainosuke", Passport: "ainosuke"}}},
 		{"藍水", script.IConvertedSlice{{Orig: "藍水", Hira: "らんすい", Kana: "ランスイ", Hepburn: "ransui", Kunrei: "ransui", Passport: "ransui"}}},
 		{"見えますか？", script.IConvertedSlice{
@@ -249,7 +268,7 @@ func TestKakasi(t *testing.T) {
 				return
 			}
 
-			if diff := cmp.Diff(converted, tt.want); diff != "" {
+			if diff := cmp.Diff(converted, tt.want, ignoreAnnotations); diff != "" {
 				t.Errorf("(*Kakasi).Convert(%q) {\"-\": got, \"+\": want}: %s", tt.args, diff)
 				return
 			}
@@ -261,3 +280,311 @@ func TestKakasi(t *testing.T) {
 		})
 	}
 }
+
+func TestKakasiOptions(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []Option
+		args string
+		want script.IConvertedSlice
+	}{
+		{"hepburn only", []Option{WithSystems(SystemHepburn)}, "漢字とひらがな", script.IConvertedSlice{
+			{Orig: "漢字", Hepburn: "kanji"},
+			{Orig: "とひらがな", Hepburn: "tohiragana"},
+		}},
+		{"hira and kana", []Option{WithSystems(SystemHira, SystemKana)}, "構成", script.IConvertedSlice{
+			{Orig: "構成", Hira: "こうせい", Kana: "コウセイ"},
+		}},
+		{"no cache", []Option{WithCacheSize(0), WithSystems(SystemKunrei)}, "漢字", script.IConvertedSlice{
+			{Orig: "漢字", Kunrei: "kanzi"},
+		}},
+		{"normalization", []Option{WithNormalization(true), WithSystems(SystemPassport)}, "ｿｳｿﾞｸﾆﾝ", script.IConvertedSlice{
+			{Orig: "ソウゾクニン", Passport: "sozokunin"},
+		}},
+		{"strict", []Option{WithStrict(true), WithSystems(SystemHira)}, "日経新聞", script.IConvertedSlice{
+			{Orig: "日経新聞", Hira: "にっけいしんぶん"},
+		}},
+		{"style", []Option{WithSystems(SystemHepburn, SystemPassport), WithStyle(SystemHepburn|SystemPassport, Style{SyllabicN: SyllabicNHyphen, LongMark: LongMarkDrop})}, "権威のコーヒー", script.IConvertedSlice{
+			{Orig: "権威", Hepburn: "ken-i", Passport: "ken-i"},
+			{Orig: "の", Hepburn: "no", Passport: "no"},
+			{Orig: "コーヒー", Hepburn: "kohi", Passport: "kohi"},
+		}},
+		{"syllabic n", []Option{WithSystems(SystemHepburn, SystemKunrei)}, "本屋と婚約者の金曜日", script.IConvertedSlice{
+			{Orig: "本屋", Hepburn: "honya", Kunrei: "honya"},
+			{Orig: "と", Hepburn: "to", Kunrei: "to"},
+			{Orig: "婚約者", Hepburn: "konyakusha", Kunrei: "konyakusya"},
+			{Orig: "の", Hepburn: "no", Kunrei: "no"},
+			{Orig: "金曜日", Hepburn: "kinyoubi", Kunrei: "kinyoubi"},
+		}},
+		{"syllabic n before y", []Option{WithSystems(SystemHepburn, SystemKunrei), WithStyle(SystemHepburn, Style{SyllabicNBeforeY: true})}, "本屋と金曜日", script.IConvertedSlice{
+			{Orig: "本屋", Hepburn: "hon'ya", Kunrei: "honya"},
+			{Orig: "と", Hepburn: "to", Kunrei: "to"},
+			{Orig: "金曜日", Hepburn: "kin'youbi", Kunrei: "kinyoubi"},
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := NewKakasi(tt.opts...)
+			if err != nil {
+				t.Errorf("NewKakasi() error: %v", err)
+				return
+			}
+
+			converted, err := k.Convert(tt.args)
+			if err != nil {
+				t.Errorf("(*Kakasi).Convert(%q) error: %v", tt.args, err)
+				return
+			}
+
+			if diff := cmp.Diff(converted, tt.want, ignoreAnnotations); diff != "" {
+				t.Errorf("(*Kakasi).Convert(%q) {\"-\": got, \"+\": want}: %s", tt.args, diff)
+			}
+		})
+	}
+}
+
+func TestKakasiOffsets(t *testing.T) {
+	type want struct {
+		orig                           string
+		start, end, byteStart, byteEnd int
+	}
+
+	for _, tt := range []struct {
+		name string
+		opts []Option
+		args string
+		want []want
+	}{
+		{"plain", nil, "漢字とひらがな", []want{{"漢字", 0, 2, 0, 6}, {"とひらがな", 2, 7, 6, 21}}},
+		{"alphabet", nil, "Alphabet 123 and 漢字", []want{{"Alphabet 123 and ", 0, 17, 0, 17}, {"漢字", 17, 19, 17, 23}}},
+		{"dropped PUA", nil, "\uF862\u6709\u9650\u4F1A\u793E", []want{{"有限会社", 1, 5, 3, 15}}},
+		{"control", nil, "漢字\n漢字", []want{{"漢字", 0, 2, 0, 6}, {"\n", 2, 3, 6, 7}, {"漢字", 3, 5, 7, 13}}},
+		{"normalized halfwidth", []Option{WithNormalization(true)}, "ｿｳｿﾞｸﾆﾝ", []want{{"ソウゾクニン", 0, 7, 0, 21}}},
+		{"normalized ligature", []Option{WithNormalization(true)}, "㍿は、", []want{{"株式会社", 0, 1, 0, 3}, {"は、", 1, 3, 3, 9}}},
+		{"normalized symbols", []Option{WithNormalization(true)}, "ﾊﾞﾆｰ〜", []want{{"バニーー", 0, 5, 0, 15}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := NewKakasi(tt.opts...)
+			if err != nil {
+				t.Errorf("NewKakasi() error: %v", err)
+				return
+			}
+
+			converted, err := k.Convert(tt.args)
+			if err != nil {
+				t.Errorf("(*Kakasi).Convert(%q) error: %v", tt.args, err)
+				return
+			}
+
+			var got []want
+			for _, v := range converted {
+				got = append(got, want{v.Orig, v.Start, v.End, v.ByteStart, v.ByteEnd})
+			}
+
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("(*Kakasi).Convert(%q) {\"-\": got, \"+\": want}: %s", tt.args, diff)
+			}
+		})
+	}
+}
+
+func TestKakasiScript(t *testing.T) {
+	type want struct {
+		orig       string
+		script     Script
+		dictionary bool
+	}
+
+	for _, tt := range []struct {
+		name string
+		args string
+		want []want
+	}{
+		{"kanji", "漢字とひらがな", []want{{"漢字", ScriptKanji, true}, {"とひらがな", ScriptHiragana, false}}},
+		{"okurigana", "交じり文", []want{{"交じり", ScriptMixed, true}, {"文", ScriptKanji, true}}},
+		{"katakana", "オレンジ色", []want{{"オレンジ", ScriptKatakana, false}, {"色", ScriptKanji, true}}},
+		{"halfwidth katakana", "ﾚﾓﾝ", []want{{"ﾚﾓﾝ", ScriptHalfwidthKatakana, false}}},
+		{"alpha", "Alphabet 123 and 漢字", []want{{"Alphabet 123 and ", ScriptAlpha, false}, {"漢字", ScriptKanji, true}}},
+		{"end mark", "日本国民は、", []want{{"日本国民", ScriptKanji, true}, {"は、", ScriptHiragana, false}}},
+		{"long symbol", "やったー", []want{{"やったー", ScriptHiragana, false}}},
+		{"symbol", "漢字「Ａ」", []want{{"漢字", ScriptKanji, true}, {"「Ａ」", ScriptSymbol, false}}},
+		{"control", "漢字\n", []want{{"漢字", ScriptKanji, true}, {"\n", ScriptSymbol, false}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := NewKakasi()
+			if err != nil {
+				t.Errorf("NewKakasi() error: %v", err)
+				return
+			}
+
+			converted, err := k.Convert(tt.args)
+			if err != nil {
+				t.Errorf("(*Kakasi).Convert(%q) error: %v", tt.args, err)
+				return
+			}
+
+			var got []want
+			for _, v := range converted {
+				got = append(got, want{v.Orig, v.Script, v.Dictionary})
+			}
+
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("(*Kakasi).Convert(%q) {\"-\": got, \"+\": want}: %s", tt.args, diff)
+			}
+		})
+	}
+}
+
+func TestPreload(t *testing.T) {
+	if err := Preload(); err != nil {
+		t.Errorf("Preload() error: %v", err)
+		return
+	}
+
+	a, err := NewKakasi(WithCacheSize(8))
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	b, err := NewKakasi(WithSystems(SystemHira))
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	for _, k := range []*Kakasi{a, b} {
+		converted, err := k.Convert("漢字")
+		if err != nil || converted[0].Hira != "かんじ" {
+			t.Errorf("(*Kakasi).Convert(%q) = (%v, %v)", "漢字", converted, err)
+		}
+	}
+
+	if a.opts.systems == b.opts.systems || a.iConv == b.iConv || a.jConv == b.jConv {
+		t.Errorf("NewKakasi() shares the options or the caches of the instances")
+	}
+}
+
+func TestConvertContext(t *testing.T) {
+	k, err := NewKakasi(WithMaxInputRunes(8))
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := k.ConvertContext(ctx, "日本国民は、"); !errors.Is(err, context.Canceled) {
+		t.Errorf("(*Kakasi).ConvertContext() error = %v, want %v", err, context.Canceled)
+	}
+
+	// a long text without end marks is scanned as a whole before its segments are committed
+	text := strings.Repeat("にほんこくみんはせいとうにせんきょされたこっかいにおけるだいひょうしゃをつうじてこうどうし", 1<<12)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	unlimited, err := NewKakasi()
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	start := time.Now()
+	if _, err := unlimited.ConvertContext(ctx, text); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("(*Kakasi).ConvertContext() error = %v, want %v", err, context.DeadlineExceeded)
+	} else if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("(*Kakasi).ConvertContext() returned after %v, want at most 500ms", elapsed)
+	}
+
+	var errTooLarge *ErrInputTooLarge
+	if _, err := k.ConvertContext(context.Background(), "日本国民は、正当に選挙"); !errors.As(err, &errTooLarge) {
+		t.Errorf("(*Kakasi).ConvertContext() error = %v, want %T", err, errTooLarge)
+	} else if errTooLarge.Limit != 8 || errTooLarge.Size != 11 {
+		t.Errorf("(*Kakasi).ConvertContext() error = %+v, want limit 8 and size 11", errTooLarge)
+	}
+
+	for _, err := range k.ConvertReader(context.Background(), strings.NewReader("日本国民は、正当に選挙")) {
+		if !errors.As(err, &errTooLarge) {
+			t.Errorf("(*Kakasi).ConvertReader() error = %v, want %T", err, errTooLarge)
+		}
+	}
+
+	if _, err := k.ConvertContext(context.Background(), "日本国民は、"); err != nil {
+		t.Errorf("(*Kakasi).ConvertContext() error = %v", err)
+	}
+}
+
+func BenchmarkConvert(b *testing.B) {
+	k, err := NewKakasi(WithCacheSize(0))
+	if err != nil {
+		b.Fatalf("NewKakasi() error: %v", err)
+	}
+
+	for _, n := range []int{1, 4, 16} {
+		text := strings.Repeat(testConstitution, n)
+		b.Run(fmt.Sprintf("x%d", n), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := k.Convert(text); err != nil {
+					b.Fatalf("(*Kakasi).Convert() error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkConvertParallel(b *testing.B) {
+	for _, bb := range []struct {
+		name string
+		opts []Option
+	}{
+		{"cached", nil},
+		{"uncached", []Option{WithCacheSize(0)}},
+	} {
+		k, err := NewKakasi(bb.opts...)
+		if err != nil {
+			b.Fatalf("NewKakasi() error: %v", err)
+		}
+
+		b.Run(bb.name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := k.Convert(testConstitution); err != nil {
+						b.Errorf("(*Kakasi).Convert() error: %v", err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestFuriganizeAligned(t *testing.T) {
+	k, err := NewKakasi()
+	if err != nil {
+		t.Fatalf("NewKakasi() error: %v", err)
+	}
+
+	converted, err := k.Convert("再び確定する学校")
+	if err != nil {
+		t.Fatalf("(*Kakasi).Convert() error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		aligner Aligner
+		want    string
+	}{
+		{nil, "再び[ふたたび]確定す[かくていす]る学校[がっこう]"},
+		{OkuriganaAligner{}, "再[ふたた]び確定[かくてい]する学校[がっこう]"},
+		{k.MonoAligner(), "再[ふたた]び確[かく]定[てい]する学[がっ]校[こう]"},
+	} {
+		if got := converted.FuriganizeAligned(FuriganaBrackets{}, tt.aligner); got != tt.want {
+			t.Errorf("IConvertedSlice.FuriganizeAligned(%T) = %q, want %q", tt.aligner, got, tt.want)
+		}
+	}
+
+	// the parts carry no romaji, thus the group rubies are kept
+	want := "<ruby>学校<rp>(</rp><rt>がっこう</rt><rp>)</rp><rtc>gakkou</rtc></ruby>"
+	if got := converted[len(converted)-1:].FuriganizeAligned(FuriganaHTML{Romaji: SystemHepburn}, k.MonoAligner()); got != want {
+		t.Errorf("IConvertedSlice.FuriganizeAligned(%T) = %q, want %q", k.MonoAligner(), got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package kakasi
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// span is a range of the input text in characters and bytes.
+type span struct {
+	start, end         int
+	byteStart, byteEnd int
+}
+
+// merge returns the smallest span covering both spans.
+func (s span) merge(o span) span {
+	return span{
+		start:     min(s.start, o.start),
+		end:       max(s.end, o.end),
+		byteStart: min(s.byteStart, o.byteStart),
+		byteEnd:   max(s.byteEnd, o.byteEnd),
+	}
+}
+
+// decodeSpans decodes the characters of p and their spans in the input text.
+// The runeOffset and byteOffset are the position of p in the input text.
+// Like in strings.Map, every invalid byte is decoded as utf8.RuneError.
+func decodeSpans(p []byte, runeOffset, byteOffset int) ([]rune, []span) {
+	runes := make([]rune, 0, len(p))
+	spans := make([]span, 0, len(p))
+	for i := 0; i < len(p); {
+		r, size := utf8.DecodeRune(p[i:])
+		runes = append(runes, r)
+		spans = append(spans, span{
+			start:     runeOffset + len(spans),
+			end:       runeOffset + len(spans) + 1,
+			byteStart: byteOffset + i,
+			byteEnd:   byteOffset + i + size,
+		})
+		i += size
+	}
+
+	return runes, spans
+}
+
+// normalizeSpans normalizes the characters like (Kakasi).Normalize.
+// It returns the normalized characters and for each of them the span of the characters it originates from.
+// Unless atEOF, the characters following the last normalization boundary are not normalized,
+// and the number of the normalized input characters is returned.
+func normalizeSpans(runes []rune, spans []span, atEOF bool) ([]rune, []span, int) {
+	mapped := make([]byte, 0, len(runes))
+	offsets := make([]int, 0, len(runes)+1) // byte offsets of the mapped characters
+	for _, r := range runes {
+		offsets = append(offsets, len(mapped))
+		mapped = utf8.AppendRune(mapped, normalizeSymbol(r))
+	}
+
+	offsets = append(offsets, len(mapped))
+	if !atEOF {
+		mapped = mapped[:max(norm.NFKC.LastBoundary(mapped), 0)]
+	}
+
+	normalized := make([]rune, 0, len(runes))
+	normalizedSpans := make([]span, 0, len(runes))
+
+	// The iterator returns long decompositions (e.g. "㍿") in several parts without advancing its position,
+	// thus the output is collected until the position advances.
+	var it norm.Iter
+	it.Init(norm.NFKC, mapped)
+	for first, pending := 0, 0; !it.Done(); {
+		for _, r := range string(it.Next()) {
+			normalized = append(normalized, r)
+			pending++
+		}
+
+		last := sort.SearchInts(offsets, it.Pos())
+		if last == first {
+			continue
+		}
+
+		source := spans[first]
+		for _, s := range spans[first+1 : last] {
+			source = source.merge(s)
+		}
+
+		for ; pending > 0; pending-- {
+			normalizedSpans = append(normalizedSpans, source)
+		}
+
+		first = last
+	}
+
+	return normalized, normalizedSpans, sort.SearchInts(offsets, len(mapped))
+}
@@ -0,0 +1,197 @@
+package kakasi
+
+import (
+	"io"
+
+	"github.com/sarumaj/go-kakasi/internal/script"
+)
+
+const (
+	SystemHira     = script.SystemHira
+	SystemKana     = script.SystemKana
+	SystemHepburn  = script.SystemHepburn
+	SystemKunrei   = script.SystemKunrei
+	SystemPassport = script.SystemPassport
+
+	SystemModifiedHepburn = script.SystemModifiedHepburn
+	SystemALALC           = script.SystemALALC
+	SystemISO3602         = script.SystemISO3602
+	SystemNihon           = script.SystemNihon
+
+	SystemDefault = script.SystemDefault
+	SystemAll     = script.SystemAll
+)
+
+const (
+	SegmentationLongest Segmentation = iota // longest dictionary match at each kanji character
+	SegmentationLattice                     // lowest cost path through the lattice of all dictionary matches
+)
+
+// Segmentation is the mode of the segmentation of kanji phrases, e.g. SegmentationLattice.
+type Segmentation int
+
+// System is a set of output systems.
+// Each system corresponds to a field of IConverted.
+// Systems can be combined with the bitwise OR operator.
+type System = script.System
+
+const (
+	SyllabicNDefault    = script.SyllabicNDefault
+	SyllabicNApostrophe = script.SyllabicNApostrophe
+	SyllabicNHyphen     = script.SyllabicNHyphen
+	SyllabicNDouble     = script.SyllabicNDouble
+	SyllabicNPlain      = script.SyllabicNPlain
+)
+
+const (
+	LongMarkDefault    = script.LongMarkDefault
+	LongMarkDouble     = script.LongMarkDouble
+	LongMarkMacron     = script.LongMarkMacron
+	LongMarkCircumflex = script.LongMarkCircumflex
+	LongMarkHyphen     = script.LongMarkHyphen
+	LongMarkDrop       = script.LongMarkDrop
+)
+
+// SyllabicN is the rendering of the syllabic n (ん) followed by a vowel, e.g. SyllabicNApostrophe.
+type SyllabicN = script.SyllabicN
+
+// LongMark is the rendering of a vowel followed by the long mark (ー), e.g. LongMarkMacron.
+type LongMark = script.LongMark
+
+// Style is the rendering of the syllabic n and the long marks of a romanization system, see WithStyle.
+type Style = script.Style
+
+// Option is a functional option of NewKakasi.
+type Option func(*options)
+
+// options is a set of settings of a Kakasi instance.
+type options struct {
+	systems        System
+	iConvCacheSize int
+	jConvCacheSize int
+	maxInputRunes  int
+	normalization  bool
+	particles      bool
+	segmentation   Segmentation
+	strict         bool
+	styles         map[System]Style
+	userDictFiles  []userDictFile
+}
+
+// userDictFile is a user dictionary file loaded by NewKakasi and the method loading its format.
+type userDictFile struct {
+	path string
+	load func(Kakasi, io.Reader) error
+}
+
+// WithCacheSize sets the capacity of the conversion caches.
+// A size of 0 disables caching.
+func WithCacheSize(size int) Option {
+	return func(o *options) {
+		o.iConvCacheSize = max(size, 0)
+		o.jConvCacheSize = max(size, 0)
+	}
+}
+
+// WithMaxInputRunes limits the number of characters of the input text.
+// Texts exceeding the limit are rejected with ErrInputTooLarge.
+// For streams, the limit applies to the total number of characters read.
+// A limit of 0 disables the check.
+func WithMaxInputRunes(limit int) Option {
+	return func(o *options) { o.maxInputRunes = max(limit, 0) }
+}
+
+// WithNormalization enables the normalization of the input text before conversion.
+// See (Kakasi).Normalize for details.
+func WithNormalization(enabled bool) Option {
+	return func(o *options) { o.normalization = enabled }
+}
+
+// WithParticles enables the romanization of the particles は, へ and を as they are read, i.e. wa, e and o.
+// を is always a particle, は and へ are particles at the boundary of a segment following a word, e.g. 私は and 東京へ,
+// after a function word or common word (ことは) and は after a particle like に (には), otherwise they are kept.
+// ISO 3602 strict and Nihon-shiki transliterate the kana, thus they romanize the particles as they are written.
+// By default, the kana are romanized as they are written, e.g. 日本国民は as nihonkokumin ha.
+func WithParticles(enabled bool) Option {
+	return func(o *options) { o.particles = enabled }
+}
+
+// WithSegmentation sets the mode of the segmentation of kanji phrases.
+// By default, the longest dictionary match is chosen at each kanji character (SegmentationLongest).
+// SegmentationLattice builds the lattice of all dictionary matches over the run of kanji and hiragana characters
+// and chooses the path with the lowest cost, which depends on the length of the phrases,
+// their source dictionary and the kana connecting them.
+func WithSegmentation(mode Segmentation) Option {
+	return func(o *options) { o.segmentation = mode }
+}
+
+// WithStrict enables the strict mode.
+// In strict mode, conversion errors are returned instead of dropping the affected segments.
+func WithStrict(enabled bool) Option {
+	return func(o *options) { o.strict = enabled }
+}
+
+// WithUserDictionaryFile loads a user dictionary file in the format of the kakasidict when the instance is created.
+// See (Kakasi).LoadUserDictionary for details.
+// The option may be given several times, the files are loaded in order.
+func WithUserDictionaryFile(path string) Option {
+	return func(o *options) {
+		o.userDictFiles = append(o.userDictFiles, userDictFile{path, Kakasi.LoadUserDictionary})
+	}
+}
+
+// WithSKKDictionaryFile loads a user dictionary file in the format of the SKK-JISYO when the instance is created.
+// The file must be encoded in UTF-8, e.g. the SKK-JISYO files distributed in EUC-JP have to be converted first.
+// See (Kakasi).LoadSKKDictionary for details.
+// The option may be given several times, the files are loaded in order.
+func WithSKKDictionaryFile(path string) Option {
+	return func(o *options) {
+		o.userDictFiles = append(o.userDictFiles, userDictFile{path, Kakasi.LoadSKKDictionary})
+	}
+}
+
+// WithStyle sets the rendering of the syllabic n and the long marks of the romanization systems.
+// The systems may be combined with the bitwise OR operator, the fields left at their zero value keep the rendering of the method,
+// e.g. Hepburn writes 権威 as ken'i, 本屋 as honya and コーヒー as koohii, while Passport writes the syllabic n as a plain n.
+// The option may be given several times, a later style of a system replaces an earlier one.
+// Nihon-shiki can be converted back to kana only in its default style.
+func WithStyle(systems System, style Style) Option {
+	return func(o *options) {
+		if o.styles == nil {
+			o.styles = make(map[System]Style)
+		}
+
+		for s := System(1); s <= systems; s <<= 1 {
+			if systems.Has(s) {
+				o.styles[s] = style
+			}
+		}
+	}
+}
+
+// WithSystems selects the output systems to compute.
+// Fields of IConverted which belong to systems not selected are left empty.
+// By default, the systems of SystemDefault are computed, i.e. all but Modified Hepburn, ALA-LC, ISO 3602 and Nihon-shiki.
+func WithSystems(systems ...System) Option {
+	return func(o *options) {
+		o.systems = 0
+		for _, s := range systems {
+			o.systems |= s
+		}
+	}
+}
+
+// newOptions returns the default options with the given options applied.
+func newOptions(opts ...Option) options {
+	o := options{
+		systems:        SystemDefault,
+		iConvCacheSize: 256,
+		jConvCacheSize: 512,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
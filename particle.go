@@ -0,0 +1,61 @@
+package kakasi
+
+import "strings"
+
+// stackedParticles are the particles which may precede the particle は, e.g. には and へは.
+var stackedParticles = map[string]bool{"へ": true, "に": true, "で": true, "と": true, "から": true, "まで": true, "より": true}
+
+// particles returns the offsets of the particles は, へ and を in a segment of hiragana.
+// を is always a particle. The run of hiragana at the beginning of the segment is split into words like by Segment,
+// は and へ are particles if they are split off as such at a segment boundary,
+// i.e. at the beginning of the run following a word of kanji, katakana or alphanumeric characters (私は, 東京へ),
+// or if they follow a function word or a common word of the run (ことは, ここへ), or は follows a particle like に (には, へは).
+// Otherwise, the characters begin or belong to a word (赤いはな, ひらがなは, 私はへや), thus they are kept.
+func (k Kakasi) particles(text []rune, seg segment) []int {
+	end := seg.start
+	for end < seg.end && k.wordClassOf(text[end], classHiragana) == classHiragana {
+		end++
+	}
+
+	run := text[seg.start:end]
+	type token struct {
+		start, end int
+		kind       TokenKind
+	}
+
+	var tokens []token
+	splitHiragana(run, func(i, j int, kind TokenKind) { tokens = append(tokens, token{i, j, kind}) })
+
+	var offsets []int
+	for n, t := range tokens {
+		if t.end != t.start+1 || !strings.ContainsRune("はへ", run[t.start]) {
+			for i := t.start; i < t.end; i++ {
+				if run[i] == 'を' {
+					offsets = append(offsets, i)
+				}
+			}
+
+			continue
+		}
+
+		var particle bool
+		switch {
+		case n == 0:
+			particle = seg.start > 0 && k.wordClassOf(text[seg.start-1], classSymbol) > classHiragana
+
+		case run[t.start] == 'は' && tokens[n-1].kind == TokenParticle:
+			particle = stackedParticles[string(run[tokens[n-1].start:tokens[n-1].end])]
+
+		default:
+			_, listed := functionWords[string(run[tokens[n-1].start:tokens[n-1].end])]
+			particle = listed && tokens[n-1].kind != TokenParticle
+
+		}
+
+		if particle {
+			offsets = append(offsets, t.start)
+		}
+	}
+
+	return offsets
+}
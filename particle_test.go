@@ -0,0 +1,59 @@
+package kakasi
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParticles(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		opts   []Option
+		system System
+		args   string
+		want   string
+	}{
+		{"strict", nil, SystemHepburn, "日本国民は、私がこの子を助けなきゃ", "nihonkokumin ha, watashi gakono ko wo tasuke nakya"},
+		{"hepburn", []Option{WithParticles(true)}, SystemHepburn, "日本国民は、私がこの子を助けなきゃ", "nihonkokumin wa, watashi gakono ko o tasuke nakya"},
+		{"kunrei", []Option{WithParticles(true)}, SystemKunrei, "東京へ行くことは", "toukyou e iku kotowa"},
+		{"nihon", []Option{WithParticles(true), WithSystems(SystemAll)}, SystemNihon, "子を助け", "ko wo tasuke"},
+		{"iso3602", []Option{WithParticles(true), WithSystems(SystemAll)}, SystemISO3602, "東京へ", "tôkyô he"},
+		{"modified hepburn", []Option{WithParticles(true), WithSystems(SystemAll)}, SystemModifiedHepburn, "私には", "watashi niwa"},
+		{"beginning", []Option{WithParticles(true)}, SystemHepburn, "はい、へいわ", "hai, heiwa"},
+		{"word", []Option{WithParticles(true)}, SystemHepburn, "行くはずだ", "iku hazuda"},
+		{"inside word", []Option{WithParticles(true)}, SystemHepburn, "ひらがなは", "hiraganaha"},
+		{"following word", []Option{WithParticles(true)}, SystemHepburn, "私はこの子をここへ", "watashi wakono ko okokoe"},
+		{"はな", []Option{WithParticles(true)}, SystemHepburn, "赤いはな", "akai hana"},
+		{"はなはだ", []Option{WithParticles(true)}, SystemHepburn, "はなはだ", "hanahada"},
+		{"はははは", []Option{WithParticles(true)}, SystemHepburn, "はははは", "hahahaha"},
+		{"へや", []Option{WithParticles(true)}, SystemHepburn, "私はへやにいる", "watashi waheyaniiru"},
+		{"へん", []Option{WithParticles(true)}, SystemHepburn, "私はへんだ", "watashi wahenda"},
+		{"ordinary word", []Option{WithParticles(true)}, SystemHepburn, "私はてがみ", "watashi wategami"},
+		{"を", []Option{WithParticles(true)}, SystemHepburn, "本をよむ", "hon oyomu"},
+		{"を before an ordinary word", []Option{WithParticles(true)}, SystemHepburn, "手をあらう", "te oarau"},
+		{"を inside a run", []Option{WithParticles(true)}, SystemHepburn, "このこをよむ", "konokooyomu"},
+		{"は before an ordinary word", []Option{WithParticles(true)}, SystemHepburn, "花はきれい", "hana wakirei"},
+		{"は before a pronoun", []Option{WithParticles(true)}, SystemHepburn, "私はあなたの", "watashi waanatano"},
+		{"へは", []Option{WithParticles(true)}, SystemHepburn, "学校へは行かない", "gakkou ewa ika nai"},
+		{"には", []Option{WithParticles(true)}, SystemHepburn, "東京には行かない", "toukyou niwa ika nai"},
+		{"では", []Option{WithParticles(true)}, SystemHepburn, "本ではない", "hon dewanai"},
+		{"katakana", []Option{WithParticles(true)}, SystemHepburn, "ペンはどこ", "pen wadoko"},
+	} {
+		k, err := NewKakasi(tt.opts...)
+		if err != nil {
+			t.Errorf("NewKakasi() error: %v", err)
+			continue
+		}
+
+		result, err := k.Convert(tt.args)
+		if err != nil {
+			t.Errorf("(*Kakasi).Convert(%q) error: %v", tt.args, err)
+			continue
+		}
+
+		if diff := cmp.Diff(result.Render(tt.system, RenderOptions{Separator: " "}), tt.want); diff != "" {
+			t.Errorf("%s: (*Kakasi).Convert(%q) {\"-\": got, \"+\": want}: %s", tt.name, tt.args, diff)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package kakasi
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sarumaj/go-kakasi/internal/script"
+)
+
+const (
+	PassportNotationHepburn = script.PassportNotationHepburn
+	PassportNotationOH      = script.PassportNotationOH
+	PassportNotationKana    = script.PassportNotationKana
+)
+
+// PassportNotation is the notation of the long vowels おお and おう of a passport name, e.g. PassportNotationOH.
+// The Hepburn notation is the rule, the others are allowed upon request of the applicant.
+type PassportNotation = script.PassportNotation
+
+// PassportName returns the name romanized in upper case by the rules of the Japanese passport, e.g. 大野 太郎 as ONO TARO,
+// or OHNO TARO in PassportNotationOH. The kanji of the name are read by the dictionary,
+// names with several readings are better given in kana. The characters other than kana and letters are dropped.
+func (k Kakasi) PassportName(name string, notation PassportNotation) (string, error) {
+	reading, err := k.readingOf(name)
+	if err != nil {
+		return "", err
+	}
+
+	return k.iConv.PassportName(reading, notation)
+}
+
+// CheckPassportName returns nil if the romanized name complies with the rules of the Japanese passport for the reading,
+// otherwise ErrPassportName. The long vowels may be written in any notation, see PassportNotation.
+// The letter case and the runs of whitespace of the romanized name are ignored.
+func (k Kakasi) CheckPassportName(romanized, reading string) error {
+	hira, err := k.readingOf(reading)
+	if err != nil {
+		return err
+	}
+
+	pattern, err := k.iConv.PassportPattern(hira)
+	if err != nil {
+		return err
+	}
+
+	if pattern.MatchString(strings.ToUpper(strings.Join(strings.Fields(romanized), " "))) {
+		return nil
+	}
+
+	want, err := k.iConv.PassportName(hira, PassportNotationHepburn)
+	if err != nil {
+		return err
+	}
+
+	return &ErrPassportName{Name: romanized, Reading: reading, Want: want}
+}
+
+// readingOf returns the kana reading of the text, the kanji are read by the dictionary.
+func (k Kakasi) readingOf(text string) (string, error) {
+	if err := k.checkInputSize(utf8.RuneCountInString(text)); err != nil {
+		return "", err
+	}
+
+	runes, spans := decodeSpans([]byte(text), 0, 0)
+	if k.opts.normalization {
+		runes, spans, _ = normalizeSpans(runes, spans, true)
+	}
+
+	var reading strings.Builder
+	_, err := k.convertSegments(context.Background(), k.newScanner(runes), spans, true, func(_ IConverted, seg segment) error {
+		reading.WriteString(seg.kana)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return reading.String(), nil
+}
@@ -0,0 +1,70 @@
+package kakasi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPassportName(t *testing.T) {
+	k, err := NewKakasi(WithSystems(SystemHepburn))
+	if err != nil {
+		t.Fatalf("NewKakasi() error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		notation PassportNotation
+		want     string
+	}{
+		{"大野 太郎", PassportNotationHepburn, "ONO TARO"},
+		{"大野 太郎", PassportNotationOH, "OHNO TAROH"},
+		{"加藤", PassportNotationKana, "KATOU"},
+		{"いのうえ しんいち", PassportNotationHepburn, "INOUE SHINICHI"},
+		{"難波", PassportNotationHepburn, "NAMBA"},
+	} {
+		got, err := k.PassportName(tt.name, tt.notation)
+		if err != nil {
+			t.Errorf("(*Kakasi).PassportName(%q, %d) error: %v", tt.name, tt.notation, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("(*Kakasi).PassportName(%q, %d) = %q, want %q", tt.name, tt.notation, got, tt.want)
+		}
+	}
+}
+
+func TestCheckPassportName(t *testing.T) {
+	k, err := NewKakasi()
+	if err != nil {
+		t.Fatalf("NewKakasi() error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		romanized, reading string
+		want               string // romanized name of the error, empty if compliant
+	}{
+		{"ONO TARO", "おおの たろう", ""},
+		{"Ohno  Taro", "おおの たろう", ""},
+		{"OONO TAROU", "オオノ タロウ", ""},
+		{"NAMBA", "なんば", ""},
+		{"NANBA", "なんば", "NAMBA"},
+		{"OUNO", "おおの", "ONO"},
+		{"INOE", "いのうえ", "INOUE"},
+	} {
+		err := k.CheckPassportName(tt.romanized, tt.reading)
+
+		var e *ErrPassportName
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("(*Kakasi).CheckPassportName(%q, %q) error: %v", tt.romanized, tt.reading, err)
+
+		case tt.want != "" && !errors.As(err, &e):
+			t.Errorf("(*Kakasi).CheckPassportName(%q, %q) = %v, want ErrPassportName", tt.romanized, tt.reading, err)
+
+		case tt.want != "" && e.Want != tt.want:
+			t.Errorf("(*Kakasi).CheckPassportName(%q, %q).Want = %q, want %q", tt.romanized, tt.reading, e.Want, tt.want)
+
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package kakasi
+
+import (
+	"slices"
+
+	"github.com/sarumaj/go-kakasi/internal/kanji"
+	"github.com/sarumaj/go-kakasi/internal/properties"
+)
+
+// segment is a span of the scanned text and its kana reading.
+type segment struct {
+	start, end int
+	kana       string
+	dictionary bool              // kana looked up in the kanwa dictionary
+	ambiguous  bool              // phrase with several readings in the dictionary
+	candidates []kanji.Candidate // readings of the phrase, if the scanner looks them up
+}
+
+// scanner splits a text into segments of the same character type.
+// It looks up the readings of kanji characters.
+// The text of the pending segment is text[start:pos].
+type scanner struct {
+	jConv *kanji.JConv
+	text  []rune
+	pos   int    // offset of the next character to scan
+	start int    // offset of the pending segment
+	kana  string // kana of the pending segment
+	dict  bool   // kana of the pending segment looked up in the kanwa dictionary
+	t     chType
+
+	// candidates enables the lookup of the candidate readings of the kanji phrases
+	candidates bool
+	// lattice enables the lattice segmentation of the kanji phrases instead of the longest match
+	lattice   bool
+	ambiguous bool              // phrase of the pending segment with several readings
+	cands     []kanji.Candidate // candidate readings of the phrase of the pending segment
+}
+
+// shift moves the offsets of the scanner by the number of characters removed from the beginning of its text.
+func (s *scanner) shift(n int) {
+	s.text, s.pos, s.start = s.text[n:], s.pos-n, s.start-n
+}
+
+// flush emits the pending segment, if any.
+func (s *scanner) flush(emit func(segment)) {
+	if s.start < s.pos {
+		emit(segment{start: s.start, end: s.pos, kana: s.kana, dictionary: s.dict, ambiguous: s.ambiguous, candidates: s.cands})
+	}
+
+	s.start, s.kana, s.dict, s.ambiguous, s.cands = s.pos, "", false, false, nil
+}
+
+// classify returns the script class of the characters of a segment.
+// End marks and long symbols belong to the class of the preceding characters.
+// The kana are classified before the symbols, since the symbol region overlaps the hiragana.
+func (s *scanner) classify(text []rune) Script {
+	var class Script
+	for _, ch := range text {
+		var c Script
+		switch {
+		case properties.Ch.IsEndmark(ch), properties.Ch.IsLongSymbol(ch):
+			continue
+
+		case kata.IsHalfWidthKana(ch):
+			c = ScriptHalfwidthKatakana
+
+		case kata.IsRegion(ch):
+			c = ScriptKatakana
+
+		case hira.IsRegion(ch):
+			c = ScriptHiragana
+
+		case alpha.IsRegion(ch):
+			c = ScriptAlpha
+
+		case symbol.IsRegion(ch):
+			c = ScriptSymbol
+
+		case s.jConv.IsRegion(ch):
+			c = ScriptKanji
+
+		default:
+			c = ScriptSymbol
+
+		}
+
+		switch class {
+		case "", c:
+			class = c
+
+		default:
+			return ScriptMixed
+
+		}
+	}
+
+	if class == "" {
+		return ScriptSymbol
+	}
+
+	return class
+}
+
+// lookahead returns the number of characters the scanner may look ahead of its position.
+func (s *scanner) lookahead() int { return 2*s.jConv.MaxKeyLen() + 1 }
+
+// scan scans the next character or kanji phrase and emits completed segments.
+func (s *scanner) scan(emit func(segment)) {
+	var fBuffer bool // output buffer flag
+	var fText bool   // output text flag
+	var fCpInc bool  // output copy and increment flag
+
+	switch ch := s.text[s.pos]; {
+
+	case properties.Ch.IsEndmark(ch):
+		fBuffer, fText, fCpInc, s.t = true, true, true, chSymbol
+
+	case properties.Ch.IsLongSymbol(ch):
+		fBuffer, fText, fCpInc = false, false, true
+
+	case symbol.IsRegion(ch):
+		fBuffer, fText, fCpInc, s.t = s.t != chSymbol, s.t == chSymbol, true, chSymbol
+
+	case kata.IsRegion(ch):
+		fBuffer, fText, fCpInc, s.t = s.t != chKana, false, true, chKana
+
+	case hira.IsRegion(ch):
+		fBuffer, fText, fCpInc, s.t = s.t != chHiragana, false, true, chHiragana
+
+	case alpha.IsRegion(ch):
+		fBuffer, fText, fCpInc, s.t = s.t != chAlpha, false, true, chAlpha
+
+	case s.jConv.IsRegion(ch):
+		bText := string(s.text[s.start:s.pos])
+		s.flush(emit)
+
+		window := s.text[s.pos:min(len(s.text), s.pos+s.lookahead())]
+		convert := s.jConv.Convert
+		if s.lattice {
+			convert = s.jConv.ConvertLattice
+		}
+
+		m, _ := convert(string(window), bText)
+		s.t = chKanji
+
+		if s.candidates {
+			s.cands, _ = s.jConv.Candidates(string(window), bText)
+			if s.lattice {
+				s.cands = promoteCandidate(s.cands, m)
+			}
+		}
+
+		if m.Length > 0 {
+			s.kana, s.dict, s.ambiguous = m.Yomi, true, m.Ambiguous
+			s.pos += m.Length
+
+		} else { // unknown kanji
+			s.pos++
+
+		}
+
+	case 0xF000 <= ch && ch <= 0xFFFD, 0x10000 <= ch && ch <= 0x10FFFD: // PUA, ignore and drop
+		s.flush(emit)
+		s.pos++
+		s.start = s.pos
+
+	default:
+		s.flush(emit)
+		s.pos++
+		s.flush(emit)
+
+	}
+
+	// convert to kana and output based on flags
+	switch {
+	case fBuffer && fText:
+		s.kana += string(s.text[s.pos])
+		s.pos++
+		s.flush(emit)
+
+	case fBuffer && fCpInc:
+		s.flush(emit)
+		s.kana = string(s.text[s.pos])
+		s.pos++
+
+	case fCpInc:
+		s.kana += string(s.text[s.pos])
+		s.pos++
+
+	}
+}
+
+// promoteCandidate moves the candidate of the chosen reading to rank 0, the other candidates keep their order.
+func promoteCandidate(cands []kanji.Candidate, m kanji.Match) []kanji.Candidate {
+	i := slices.IndexFunc(cands, func(c kanji.Candidate) bool { return c.Yomi == m.Yomi && c.Length == m.Length })
+	if i <= 0 {
+		return cands
+	}
+
+	c := cands[i]
+	cands = slices.Insert(slices.Delete(cands, i, i+1), 0, c)
+	for rank := range cands {
+		cands[rank].Rank = rank
+	}
+
+	return cands
+}
@@ -0,0 +1,226 @@
+package kakasi
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sarumaj/go-kakasi/internal/properties"
+)
+
+const (
+	TokenWord      TokenKind = "Word"
+	TokenParticle  TokenKind = "Particle"
+	TokenAuxiliary TokenKind = "Auxiliary"
+	TokenSymbol    TokenKind = "Symbol"
+)
+
+// TokenKind is the kind of a token, e.g. TokenParticle.
+type TokenKind string
+
+// Token is a word of the input text and its reading.
+type Token struct {
+	Surface   string    `json:"surface"`
+	Reading   string    `json:"reading"`    // reading in hiragana, empty for kanji without a reading
+	Kind      TokenKind `json:"kind"`       // kind of the word
+	Start     int       `json:"start"`      // offset of the token in the input text in characters
+	End       int       `json:"end"`        // end offset of the token in the input text in characters
+	ByteStart int       `json:"byte_start"` // offset of the token in the input text in bytes
+	ByteEnd   int       `json:"byte_end"`   // end offset of the token in the input text in bytes
+}
+
+// functionWords are the particles and the auxiliaries which split the runs of hiragana,
+// along with common words written in hiragana, which are kept whole.
+var functionWords = func() map[string]TokenKind {
+	words := make(map[string]TokenKind)
+	for kind, list := range map[TokenKind]string{
+		TokenParticle: "は が を に へ と で も や の から まで より ね よ か な って けど けれど ので のに ば" +
+			" しか だけ ほど など くらい ぐらい ながら でも ても し さえ こそ ばかり",
+		TokenAuxiliary: "です でした でしょう だ だっ だろう た て ない なかっ なきゃ なければ ます まし ました ません ませ" +
+			" れる られる せる させる たい たかっ う よう らしい そう ちゃ じゃ",
+		TokenWord: "する いる ある なる あり あっ なっ なり こと もの とき ところ ため さん" +
+			" この その あの どの これ それ あれ どれ ここ そこ あそこ どこ ありがとう ください はず はじめ",
+	} {
+		for _, w := range strings.Fields(list) {
+			words[w] = kind
+		}
+	}
+
+	return words
+}()
+
+// functionWordsMaxLen is the length of the longest function word in characters.
+var functionWordsMaxLen = func() (n int) {
+	for w := range functionWords {
+		n = max(n, utf8.RuneCountInString(w))
+	}
+
+	return
+}()
+
+// Segment splits the input text into words like the -w mode of KAKASI (wakati-gaki).
+// The kanji phrases found in the dictionary are words along with their okurigana.
+// The runs of hiragana are split at the particles and auxiliaries of a built-in list,
+// inside a word only function words of several characters split it, which keeps words like ひらがな whole.
+// The other runs of characters of the same script are words, the symbols are tokens of their own and spaces are dropped.
+func (k Kakasi) Segment(text string) ([]Token, error) {
+	if err := k.checkInputSize(utf8.RuneCountInString(text)); err != nil {
+		return nil, err
+	}
+
+	runes, spans := decodeSpans([]byte(text), 0, 0)
+	if k.opts.normalization {
+		runes, spans, _ = normalizeSpans(runes, spans, true)
+	}
+
+	var tokens []Token
+	emit := func(start, end int, reading string, kind TokenKind) {
+		tokens = append(tokens, Token{
+			Surface:   string(runes[start:end]),
+			Reading:   reading,
+			Kind:      kind,
+			Start:     spans[start].start,
+			End:       spans[end-1].end,
+			ByteStart: spans[start].byteStart,
+			ByteEnd:   spans[end-1].byteEnd,
+		})
+	}
+
+	_, err := k.convertSegments(context.Background(), k.newScanner(runes), spans, true, func(_ IConverted, seg segment) error {
+		return k.segmentWords(runes, seg, emit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// wordClass is the class of the characters of a word.
+type wordClass int
+
+const (
+	classSymbol wordClass = iota
+	classSpace
+	classHiragana
+	classKatakana
+	classKanji
+	classAlnum
+)
+
+// wordClassOf returns the class of a character, the long symbols belong to the class of the preceding character.
+func (k Kakasi) wordClassOf(ch rune, preceding wordClass) wordClass {
+	switch {
+	case properties.Ch.IsLongSymbol(ch) && preceding > classSpace:
+		return preceding
+
+	case unicode.IsSpace(ch):
+		return classSpace
+
+	case unicode.Is(unicode.Hiragana, ch):
+		return classHiragana
+
+	case unicode.Is(unicode.Katakana, ch), 0xFF9E <= ch && ch <= 0xFF9F: // halfwidth sound marks
+		return classKatakana
+
+	case k.jConv.IsRegion(ch):
+		return classKanji
+
+	case unicode.IsLetter(ch), unicode.IsNumber(ch):
+		return classAlnum
+
+	default:
+		return classSymbol
+
+	}
+}
+
+// segmentWords splits a converted segment of the text into words and passes them to emit.
+func (k Kakasi) segmentWords(text []rune, seg segment, emit func(start, end int, reading string, kind TokenKind)) error {
+	start, end := seg.start, seg.end
+	if seg.dictionary {
+		// the end marks joined to the kanji phrase are symbols of their own
+		phrase := end
+		for phrase > start+1 && properties.Ch.IsEndmark(text[phrase-1]) {
+			phrase--
+		}
+
+		kana := []rune(seg.kana)
+		emit(start, phrase, string(kana[:len(kana)-(end-phrase)]), TokenWord)
+		start = phrase
+	}
+
+	for start < end {
+		class := k.wordClassOf(text[start], classSymbol)
+		stop := start + 1
+		for class != classSymbol && stop < end && k.wordClassOf(text[stop], class) == class {
+			stop++
+		}
+
+		switch class {
+		case classSpace:
+
+		case classSymbol:
+			emit(start, stop, string(text[start:stop]), TokenSymbol)
+
+		case classHiragana:
+			splitHiragana(text[start:stop], func(i, j int, kind TokenKind) {
+				emit(start+i, start+j, string(text[start+i:start+j]), kind)
+			})
+
+		case classKanji: // kanji without a reading
+			emit(start, stop, "", TokenWord)
+
+		default:
+			reading, err := k.iConv.Hiragana(string(text[start:stop]))
+			if err != nil {
+				return err
+			}
+
+			emit(start, stop, reading, TokenWord)
+
+		}
+
+		start = stop
+	}
+
+	return nil
+}
+
+// splitHiragana splits a run of hiragana into words at the function words and passes their offsets to emit.
+func splitHiragana(run []rune, emit func(start, end int, kind TokenKind)) {
+	word := 0 // offset of the pending word
+	for i := 0; i < len(run); {
+		l, kind := matchFunctionWord(run[i:], i == word)
+		if l == 0 {
+			i++
+			continue
+		}
+
+		if word < i {
+			emit(word, i, TokenWord)
+		}
+
+		emit(i, i+l, kind)
+		i += l
+		word = i
+	}
+
+	if word < len(run) {
+		emit(word, len(run), TokenWord)
+	}
+}
+
+// matchFunctionWord returns the length and the kind of the longest function word at the beginning of the text.
+// Unless at a word boundary, only particles and auxiliaries of several characters match.
+func matchFunctionWord(text []rune, boundary bool) (int, TokenKind) {
+	for l := min(len(text), functionWordsMaxLen); l > 0; l-- {
+		kind, ok := functionWords[string(text[:l])]
+		if ok && (boundary || l > 1 && kind != TokenWord) {
+			return l, kind
+		}
+	}
+
+	return 0, ""
+}
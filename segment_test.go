@@ -0,0 +1,77 @@
+package kakasi
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSegment(t *testing.T) {
+	k, err := NewKakasi()
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	for _, tt := range []struct {
+		args string
+		want []string
+	}{
+		{"日本国民は、私がこの子を助けなきゃいけないってことだよね。", []string{
+			"日本国民:にほんこくみん:Word", "は:は:Particle", "、:、:Symbol",
+			"私:わたし:Word", "が:が:Particle", "この:この:Word", "子:こ:Word", "を:を:Particle",
+			"助け:たすけ:Word", "なきゃ:なきゃ:Auxiliary", "いけ:いけ:Word", "ない:ない:Auxiliary", "って:って:Particle",
+			"こと:こと:Word", "だ:だ:Auxiliary", "よ:よ:Particle", "ね:ね:Particle", "。:。:Symbol",
+		}},
+		{"漢字とひらがな交じり文", []string{
+			"漢字:かんじ:Word", "と:と:Particle", "ひらがな:ひらがな:Word", "交じり:まじり:Word", "文:ぶん:Word",
+		}},
+		{"オレンジジュースを飲みました。", []string{
+			"オレンジジュース:おれんじじゅーす:Word", "を:を:Particle", "飲み:のみ:Word", "ました:ました:Auxiliary", "。:。:Symbol",
+		}},
+		{"Alphabet 123 and 漢字", []string{
+			"Alphabet:Alphabet:Word", "123:123:Word", "and:and:Word", "漢字:かんじ:Word",
+		}},
+		{"ありがとうございます", []string{"ありがとう:ありがとう:Word", "ござい:ござい:Word", "ます:ます:Auxiliary"}},
+	} {
+		tokens, err := k.Segment(tt.args)
+		if err != nil {
+			t.Errorf("(*Kakasi).Segment(%q) error: %v", tt.args, err)
+			continue
+		}
+
+		var got []string
+		for _, v := range tokens {
+			got = append(got, v.Surface+":"+v.Reading+":"+string(v.Kind))
+		}
+
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("(*Kakasi).Segment(%q) {\"-\": got, \"+\": want}: %s", tt.args, diff)
+		}
+	}
+}
+
+func TestSegmentOffsets(t *testing.T) {
+	k, err := NewKakasi(WithNormalization(true))
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	args := "ｶﾞｷﾞ　です！"
+	got, err := k.Segment(args)
+	if err != nil {
+		t.Errorf("(*Kakasi).Segment(%q) error: %v", args, err)
+		return
+	}
+
+	want := []Token{
+		{Surface: "ガギ", Reading: "がぎ", Kind: TokenWord, Start: 0, End: 4, ByteStart: 0, ByteEnd: 12},
+		{Surface: "です", Reading: "です", Kind: TokenAuxiliary, Start: 5, End: 7, ByteStart: 15, ByteEnd: 21},
+		{Surface: "!", Reading: "!", Kind: TokenSymbol, Start: 7, End: 8, ByteStart: 21, ByteEnd: 24},
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("(*Kakasi).Segment(%q) {\"-\": got, \"+\": want}: %s", args, diff)
+	}
+}
@@ -0,0 +1,82 @@
+package kakasi
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestSegmentationCorpus(t *testing.T) {
+	longest, err := NewKakasi()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lattice, err := NewKakasi(WithSegmentation(SegmentationLattice))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filepath.Join("testdata", "segmentation.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	var got strings.Builder
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(&got, "%s\t%s\t%s\n", line, segmentsOf(t, longest, line), segmentsOf(t, lattice, line))
+	}
+
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "segmentation.golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(got.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(got.String(), string(want)); diff != "" {
+		t.Errorf("segmentation corpus {\"-\": got, \"+\": want}: %s", diff)
+	}
+}
+
+// segmentsOf returns the readings of the segments of the text separated by spaces.
+func segmentsOf(t *testing.T, k *Kakasi, text string) string {
+	t.Helper()
+
+	result, err := k.Convert(text)
+	if err != nil {
+		t.Fatalf("(*Kakasi).Convert(%q) error = %v", text, err)
+	}
+
+	segments := make([]string, len(result))
+	for i, r := range result {
+		segments[i] = r.Hira
+	}
+
+	return strings.Join(segments, " ")
+}
@@ -0,0 +1,213 @@
+package kakasi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"math/bits"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// chunkSize is the number of bytes read from a stream at once.
+const chunkSize = 4096
+
+// errStop is returned by yield functions to stop the conversion of a stream.
+var errStop = errors.New("stop")
+
+// ConvertReader converts the text read from r to kana/romaji.
+// The segments are yielded as soon as they are final,
+// thus the sequence of the yielded segments equals the result of Convert for the whole text,
+// including the single empty segment of an empty text.
+// Segments are final once the scanner has moved past them, e.g. at the next change of the script,
+// thus only the pending segment and the lookahead of the kanji phrases are buffered, and each character is scanned once.
+// The conversion stops on the first error, including a cancellation of the context
+// and ErrInputTooLarge if the stream exceeds the limit set by WithMaxInputRunes.
+// The text read before a read error is converted like the end of the text, before the error is yielded.
+func (k Kakasi) ConvertReader(ctx context.Context, r io.Reader) iter.Seq2[IConverted, error] {
+	return func(yield func(IConverted, error) bool) {
+		s := stream{normalization: k.opts.normalization}
+		chunk := make([]byte, chunkSize)
+		for atEOF := false; !atEOF; {
+			if err := ctx.Err(); err != nil {
+				yield(IConverted{}, err)
+				return
+			}
+
+			// the bytes returned along with an error are converted as the end of the text, see io.Reader
+			n, readErr := r.Read(chunk)
+			atEOF = readErr != nil
+			if errors.Is(readErr, io.EOF) {
+				readErr = nil
+			}
+
+			s.write(chunk[:n], atEOF)
+			if err := k.checkInputSize(s.size); err != nil {
+				yield(IConverted{}, err)
+				return
+			}
+
+			err := s.convert(ctx, k, atEOF, func(result IConverted) error {
+				if !yield(result, nil) {
+					return errStop
+				}
+
+				return nil
+			})
+
+			switch {
+			case errors.Is(err, errStop):
+				return
+
+			case err != nil:
+				yield(IConverted{}, err)
+				return
+
+			case readErr != nil:
+				yield(IConverted{}, readErr)
+				return
+
+			case atEOF && s.size == 0:
+				// like Convert, an empty text is converted to a single empty segment
+				yield(IConverted{}, nil)
+				return
+
+			}
+		}
+	}
+}
+
+// NewRomanizer returns a transformer which converts Japanese text to the given output system, e.g. SystemHepburn.
+// The converted segments are separated by a single space,
+// thus for SystemHepburn the output equals the result of (IConvertedSlice).Romanize for the whole text.
+// Like ConvertReader, the transformer buffers the text until the segments are final.
+func (k Kakasi) NewRomanizer(system System) transform.Transformer {
+	r := &romanizer{k: k, system: system}
+	r.Reset()
+	return r
+}
+
+// fieldOf returns the field of the converted text which corresponds to the given output system.
+// It returns the Hepburn field unless system is a single system.
+func fieldOf(v IConverted, system System) string {
+	if bits.OnesCount(uint(system)) != 1 {
+		return v.Hepburn
+	}
+
+	return v.Field(system)
+}
+
+// romanizer is a transformer which converts Japanese text to an output system.
+type romanizer struct {
+	k      Kakasi
+	system System
+	stream stream
+	out    []byte // transformed bytes not yet written to dst
+	sep    bool   // a separator precedes the next segment
+}
+
+// Reset resets the state of the romanizer.
+func (r *romanizer) Reset() {
+	*r = romanizer{k: r.k, system: r.system, stream: stream{normalization: r.k.opts.normalization}}
+}
+
+// Transform implements the transform.Transformer interface.
+// The whole src is consumed, the characters which are not final yet are buffered by the romanizer.
+func (r *romanizer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	nDst = copy(dst, r.out)
+	r.out = r.out[nDst:]
+	if len(r.out) > 0 {
+		return nDst, 0, transform.ErrShortDst
+	}
+
+	r.stream.write(src, atEOF)
+	nSrc = len(src)
+	if err := r.k.checkInputSize(r.stream.size); err != nil {
+		return nDst, nSrc, err
+	}
+
+	err = r.stream.convert(context.Background(), r.k, atEOF, func(result IConverted) error {
+		if r.sep {
+			r.out = append(r.out, ' ')
+		}
+
+		r.out = append(r.out, fieldOf(result, r.system)...)
+		r.sep = true
+		return nil
+	})
+	if err != nil {
+		return nDst, nSrc, err
+	}
+
+	n := copy(dst[nDst:], r.out)
+	nDst += n
+	r.out = r.out[n:]
+	if len(r.out) > 0 {
+		return nDst, nSrc, transform.ErrShortDst
+	}
+
+	return nDst, nSrc, nil
+}
+
+// stream is a buffer of a text which is converted in chunks.
+// The characters are normalized as they are written, if the normalization is enabled.
+type stream struct {
+	normalization bool
+	partial       []byte // incomplete UTF-8 sequence at the end of the last chunk
+	raw           []rune // characters which are not normalized yet
+	rawSpans      []span
+	text          []rune // characters which are not converted yet
+	spans         []span
+	size          int      // number of characters written
+	byteSize      int      // number of bytes written, excluding the incomplete UTF-8 sequence
+	scanner       *scanner // scanner of the text, which resumes at its position on the next chunk
+}
+
+// convert converts the buffered text and passes the final segments to yield.
+// The characters of the converted segments are removed from the buffer,
+// except for the last one, which is the context of the following segment, see (Kakasi).particles.
+func (s *stream) convert(ctx context.Context, k Kakasi, atEOF bool, yield func(IConverted) error) error {
+	if s.scanner == nil {
+		s.scanner = k.newScanner(nil)
+	}
+
+	s.scanner.text = s.text
+	n, err := k.convertSegments(ctx, s.scanner, s.spans, atEOF, func(result IConverted, _ segment) error {
+		return yield(result)
+	})
+
+	n = max(n-1, 0)
+	s.scanner.shift(n)
+	s.text = append(s.text[:0], s.text[n:]...)
+	s.spans = append(s.spans[:0], s.spans[n:]...)
+	return err
+}
+
+// write decodes a chunk and appends its characters to the buffer.
+// Unless atEOF, an incomplete UTF-8 sequence at the end of the chunk is retained for the next chunk,
+// and the characters following the last normalization boundary are retained until the next chunk.
+func (s *stream) write(p []byte, atEOF bool) {
+	s.partial = append(s.partial, p...)
+
+	var n int
+	for n < len(s.partial) && (atEOF || utf8.FullRune(s.partial[n:])) {
+		_, size := utf8.DecodeRune(s.partial[n:])
+		n += size
+	}
+
+	runes, spans := decodeSpans(s.partial[:n], s.size, s.byteSize)
+	s.partial = append(s.partial[:0], s.partial[n:]...)
+	s.size, s.byteSize = s.size+len(runes), s.byteSize+n
+
+	if !s.normalization {
+		s.text, s.spans = append(s.text, runes...), append(s.spans, spans...)
+		return
+	}
+
+	s.raw, s.rawSpans = append(s.raw, runes...), append(s.rawSpans, spans...)
+	runes, spans, n = normalizeSpans(s.raw, s.rawSpans, atEOF)
+	s.text, s.spans = append(s.text, runes...), append(s.spans, spans...)
+	s.raw, s.rawSpans = append(s.raw[:0], s.raw[n:]...), append(s.rawSpans[:0], s.rawSpans[n:]...)
+}
@@ -0,0 +1,184 @@
+package kakasi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/transform"
+)
+
+const testConstitution = "日本国民は、正当に選挙された国会における代表者を通じて行動し、われらとわれらの子孫のために、" +
+	"諸国民との協和による成果と、わが国全土にわたつて自由のもたらす恵沢を確保し、" +
+	"政府の行為によつて再び戦争の惨禍が起ることのないやうにすることを決意し、ここに主権が国民に存することを宣言し、" +
+	"この憲法を確定する。そもそも国政は、国民の厳粛な信託によるものであつて、その権威は国民に由来し、" +
+	"その権力は国民の代表者がこれを行使し、その福利は国民がこれを享受する。これは人類普遍の原理であり、" +
+	"この憲法は、かかる原理に基くものである。われらは、これに反する一切の憲法、法令及び詔勅を排除する。"
+
+// testUnpunctuated is a long text without punctuation, thus without end marks of the sentences.
+var testUnpunctuated = strings.Repeat(strings.NewReplacer("、", "", "。", "").Replace(testConstitution), 64)
+
+func TestConvertReader(t *testing.T) {
+	k, err := NewKakasi()
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	for _, tt := range []struct {
+		name string
+		args string
+	}{
+		{"constitution", testConstitution},
+		{"lines", "思った\n言った\n行った\n"},
+		{"mixed", "Alphabet 123 and 漢字\n有限会社。バニーちゃんちのシャワーノズルの先端"},
+		{"unpunctuated", testUnpunctuated},
+		{"empty", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := k.Convert(tt.args)
+			if err != nil {
+				t.Errorf("(*Kakasi).Convert(%q) error: %v", tt.args, err)
+				return
+			}
+
+			var got IConvertedSlice
+			for result, err := range k.ConvertReader(context.Background(), iotest.OneByteReader(strings.NewReader(tt.args))) {
+				if err != nil {
+					t.Errorf("(*Kakasi).ConvertReader(%q) error: %v", tt.args, err)
+					return
+				}
+
+				got = append(got, result)
+			}
+
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("(*Kakasi).ConvertReader(%q) {\"-\": got, \"+\": want}: %s", tt.args, diff)
+			}
+		})
+	}
+}
+
+func BenchmarkConvertReader(b *testing.B) {
+	k, err := NewKakasi(WithCacheSize(0))
+	if err != nil {
+		b.Fatalf("NewKakasi() error: %v", err)
+	}
+
+	for _, n := range []int{1, 4, 16} {
+		text := strings.Repeat(testUnpunctuated, n)
+		b.Run(fmt.Sprintf("x%d", n), func(b *testing.B) {
+			for b.Loop() {
+				for _, err := range k.ConvertReader(context.Background(), strings.NewReader(text)) {
+					if err != nil {
+						b.Fatalf("(*Kakasi).ConvertReader() error: %v", err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestConvertReaderError(t *testing.T) {
+	k, err := NewKakasi()
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	// the text is returned along with the error, which must be converted before the error is yielded
+	args, want := "漢字とひらがな。", []string{"かんじ", "とひらがな。"}
+	wantErr := errors.New("read error")
+
+	var got []string
+	var gotErr error
+	for result, err := range k.ConvertReader(context.Background(), &errReader{args, wantErr}) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+
+		got = append(got, result.Hira)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("(*Kakasi).ConvertReader(%q) {\"-\": got, \"+\": want}: %s", args, diff)
+	}
+
+	if gotErr != wantErr {
+		t.Errorf("(*Kakasi).ConvertReader(%q) error = %v, want %v", args, gotErr, wantErr)
+	}
+}
+
+// errReader returns its text along with an error on the first read.
+type errReader struct {
+	text string
+	err  error
+}
+
+// Read implements the io.Reader interface.
+func (r *errReader) Read(p []byte) (int, error) {
+	n := copy(p, r.text)
+	r.text = r.text[n:]
+	return n, r.err
+}
+
+func TestConvertReaderCanceled(t *testing.T) {
+	k, err := NewKakasi()
+	if err != nil {
+		t.Errorf("NewKakasi() error: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, err := range k.ConvertReader(ctx, strings.NewReader(testConstitution)) {
+		if err != context.Canceled {
+			t.Errorf("(*Kakasi).ConvertReader() error = %v, want %v", err, context.Canceled)
+		}
+	}
+}
+
+func TestNewRomanizer(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		opts   []Option
+		system System
+		args   string
+		want   func(IConvertedSlice) string
+	}{
+		{"hepburn", nil, SystemHepburn, testConstitution, IConvertedSlice.Romanize},
+		{"kunrei", nil, SystemKunrei, "漢字とひらがな交じり文", func(v IConvertedSlice) string { return "kanzi tohiragana maziri bun" }},
+		{"normalized", []Option{WithNormalization(true)}, SystemPassport, "ｿｳｿﾞｸﾆﾝ", func(v IConvertedSlice) string { return "sozokunin" }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := NewKakasi(tt.opts...)
+			if err != nil {
+				t.Errorf("NewKakasi() error: %v", err)
+				return
+			}
+
+			converted, err := k.Convert(tt.args)
+			if err != nil {
+				t.Errorf("(*Kakasi).Convert(%q) error: %v", tt.args, err)
+				return
+			}
+
+			got, err := io.ReadAll(transform.NewReader(iotest.HalfReader(strings.NewReader(tt.args)), k.NewRomanizer(tt.system)))
+			if err != nil {
+				t.Errorf("(*Kakasi).NewRomanizer(%v) error: %v", tt.system, err)
+				return
+			}
+
+			if want := tt.want(converted); string(got) != want {
+				t.Errorf("(*Kakasi).NewRomanizer(%v) = %q, want %q", tt.system, got, want)
+			}
+		})
+	}
+}